@@ -0,0 +1,108 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Project is what already lives in a directory
+type Project struct {
+	JS   bool   // has a package.json
+	Next string // version range of next from package.json, empty when absent
+	Git  bool   // is the root of a git repository or worktree
+}
+
+// Detect looks for a package.json and a .git entry in dir
+func Detect(dir string) Project {
+	var p Project
+	if _, err := os.Lstat(filepath.Join(dir, ".git")); err == nil {
+		p.Git = true
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return p
+	}
+	p.JS = true
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if json.Unmarshal(data, &pkg) == nil {
+		p.Next = pkg.Dependencies["next"]
+		if p.Next == "" {
+			p.Next = pkg.DevDependencies["next"]
+		}
+	}
+	return p
+}
+
+// Any reports whether anything was detected
+func (p Project) Any() bool {
+	return p.JS || p.Git
+}
+
+// Tags describes the project in short labels, like ["next 15.5.4", "git"]
+func (p Project) Tags() []string {
+	var tags []string
+	switch {
+	case p.Next != "":
+		tags = append(tags, "next "+strings.TrimLeft(p.Next, "^~"))
+	case p.JS:
+		tags = append(tags, "js")
+	}
+	if p.Git {
+		tags = append(tags, "git")
+	}
+	return tags
+}
+
+// Preview is a short listing of a directory's children
+type Preview struct {
+	Path    string
+	Project Project
+	Entries []Entry
+	More    bool // the directory has more than the listed entries
+	Err     error
+}
+
+// LoadPreview lists up to limit children of dir, hidden ones included since
+// they tell what the directory is. Only limit+1 entries are read, so a huge
+// directory costs no more than a small one.
+func LoadPreview(ctx context.Context, dir string, limit int) Preview {
+	p := Preview{Path: dir, Project: Detect(dir)}
+	f, err := os.Open(dir)
+	if err != nil {
+		p.Err = err
+		return p
+	}
+	defer f.Close()
+	dirEntries, err := f.ReadDir(limit + 1)
+	if err != nil && err != io.EOF {
+		p.Err = err
+		return p
+	}
+	if len(dirEntries) > limit {
+		dirEntries, p.More = dirEntries[:limit], true
+	}
+	// Unlike os.ReadDir, File.ReadDir returns entries in directory order
+	sort.Slice(dirEntries, func(i, j int) bool { return dirEntries[i].Name() < dirEntries[j].Name() })
+	for _, d := range dirEntries {
+		if ctx.Err() != nil {
+			p.Err = ctx.Err()
+			return p
+		}
+		e := Entry{Name: d.Name(), Path: filepath.Join(dir, d.Name()), IsDir: d.IsDir()}
+		if d.Type()&os.ModeSymlink != 0 {
+			if info, err := os.Stat(e.Path); err == nil {
+				e.IsDir = info.IsDir()
+			}
+		}
+		p.Entries = append(p.Entries, e)
+	}
+	return p
+}
@@ -0,0 +1,86 @@
+package browse
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		tags  []string
+	}{
+		{"empty", nil, nil},
+		{"next", map[string]string{"package.json": `{"dependencies":{"next":"^15.5.4"}}`}, []string{"next 15.5.4"}},
+		{"next dev dependency", map[string]string{"package.json": `{"devDependencies":{"next":"~14.2.0"}}`}, []string{"next 14.2.0"}},
+		{"plain js", map[string]string{"package.json": `{"name":"lib"}`}, []string{"js"}},
+		{"broken package.json", map[string]string{"package.json": `{`}, []string{"js"}},
+		{"git", map[string]string{".git/HEAD": "ref: refs/heads/main"}, []string{"git"}},
+		{"worktree", map[string]string{".git": "gitdir: ../main/.git/worktrees/x"}, []string{"git"}},
+		{"next and git", map[string]string{"package.json": `{"dependencies":{"next":"15.0.0"}}`, ".git/HEAD": ""}, []string{"next 15.0.0", "git"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(dir, name)
+				os.MkdirAll(filepath.Dir(path), 0755)
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			p := Detect(dir)
+			if got := p.Tags(); !equal(got, tt.tags) {
+				t.Errorf("Tags() = %v, want %v", got, tt.tags)
+			}
+			if p.Any() != (len(tt.tags) > 0) {
+				t.Errorf("Any() = %t with tags %v", p.Any(), tt.tags)
+			}
+		})
+	}
+}
+
+func TestLoadPreview(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 5; i++ {
+		mkdirs(t, dir, fmt.Sprintf("f%d", i))
+	}
+	mkdirs(t, dir, "sub/", ".env")
+
+	p := LoadPreview(context.Background(), dir, 10)
+	if p.Err != nil || p.More {
+		t.Fatalf("Err %v More %t, want the whole listing", p.Err, p.More)
+	}
+	if got, want := names(p.Entries), []string{".env", "f0", "f1", "f2", "f3", "f4", "sub"}; !equal(got, want) {
+		t.Errorf("entries %v, want %v", got, want)
+	}
+	for _, e := range p.Entries {
+		if e.IsDir != (e.Name == "sub") {
+			t.Errorf("%s IsDir = %t", e.Name, e.IsDir)
+		}
+	}
+
+	p = LoadPreview(context.Background(), dir, 3)
+	if len(p.Entries) != 3 || !p.More {
+		t.Errorf("got %d entries, More %t; want 3 and more", len(p.Entries), p.More)
+	}
+	if p = LoadPreview(context.Background(), dir, 7); p.More {
+		t.Error("More set although every entry fit")
+	}
+
+	p = LoadPreview(context.Background(), filepath.Join(dir, "missing"), 3)
+	if p.Err == nil || !strings.Contains(Reason(p.Err), "no such file") {
+		t.Errorf("Err = %v, want not found", p.Err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if p = LoadPreview(ctx, dir, 10); p.Err != context.Canceled {
+		t.Errorf("cancelled preview Err = %v", p.Err)
+	}
+}
@@ -0,0 +1,217 @@
+package browse
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxCounted caps how many children of a directory are counted for its size
+const MaxCounted = 1000
+
+// Entry is one item of a directory listing
+type Entry struct {
+	Name  string
+	Path  string
+	IsDir bool // directories and symlinks to directories
+	// Link is the symlink target as written in the link, empty for plain entries
+	Link string
+	// Broken marks a symlink whose target does not exist
+	Broken bool
+	// Locked marks a directory that cannot be opened (permissions, dead mount)
+	Locked bool
+	// Matched are the byte offsets in Name that matched the search query
+	Matched []int
+	// ModTime is when the entry last changed
+	ModTime time.Time
+	// Size is the size in bytes of a file, or how many entries a directory
+	// holds, up to MaxCounted. Directories are only counted when the listing
+	// is sorted by size.
+	Size int64
+}
+
+// SortMode is the order of a listing; directories always come first
+type SortMode int
+
+const (
+	SortName     SortMode = iota
+	SortModified          // newest first
+	SortSize              // largest first
+)
+
+func (s SortMode) String() string {
+	switch s {
+	case SortModified:
+		return "modified"
+	case SortSize:
+		return "size"
+	}
+	return "name"
+}
+
+// Next cycles to the following sort mode
+func (s SortMode) Next() SortMode {
+	return (s + 1) % 3
+}
+
+// Options pick which entries a listing shows and in what order
+type Options struct {
+	Files  bool // list files too, not only directories
+	Hidden bool // list entries starting with a dot
+	Sort   SortMode
+}
+
+// less orders directories first, then by the sort mode, then by name
+func less(a, b Entry, mode SortMode) bool {
+	if a.IsDir != b.IsDir {
+		return a.IsDir
+	}
+	switch {
+	case mode == SortModified && !a.ModTime.Equal(b.ModTime):
+		return a.ModTime.After(b.ModTime)
+	case mode == SortSize && a.Size != b.Size:
+		return a.Size > b.Size
+	}
+	return a.Name < b.Name
+}
+
+// Sort orders entries in place
+func Sort(entries []Entry, mode SortMode) {
+	sort.SliceStable(entries, func(i, j int) bool { return less(entries[i], entries[j], mode) })
+}
+
+// Merge adds a page of entries to a listing sorted by mode, keeping it sorted
+func Merge(sorted, page []Entry, mode SortMode) []Entry {
+	page = append([]Entry(nil), page...)
+	Sort(page, mode)
+	merged := make([]Entry, 0, len(sorted)+len(page))
+	i, j := 0, 0
+	for i < len(sorted) && j < len(page) {
+		if less(page[j], sorted[i], mode) {
+			merged = append(merged, page[j])
+			j++
+		} else {
+			merged = append(merged, sorted[i])
+			i++
+		}
+	}
+	merged = append(merged, sorted[i:]...)
+	return append(merged, page[j:]...)
+}
+
+// Lister reads a directory a page at a time, so huge folders and slow mounts
+// can be shown while they load
+type Lister struct {
+	path string
+	dir  *os.File
+	opts Options
+}
+
+// Open starts listing path with the entries opts asks for
+func Open(path string, opts Options) (*Lister, error) {
+	dir, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Lister{path: path, dir: dir, opts: opts}, nil
+}
+
+// Next returns up to n more entries, unsorted, and io.EOF once the directory
+// is exhausted. Fewer than n come back when some are filtered out. It stops
+// early with ctx's error when ctx is cancelled.
+func (l *Lister) Next(ctx context.Context, n int) ([]Entry, error) {
+	dirEntries, err := l.dir.ReadDir(n)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(dirEntries))
+	for _, d := range dirEntries {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if !l.opts.Hidden && strings.HasPrefix(d.Name(), ".") {
+			continue
+		}
+		e := inspect(l.path, d, l.opts.Sort == SortSize)
+		// Broken links may have pointed at a directory, so they stay
+		if !l.opts.Files && !e.IsDir && !e.Broken {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, err
+}
+
+// Close releases the directory
+func (l *Lister) Close() error {
+	return l.dir.Close()
+}
+
+// inspect describes an entry, counting a directory's children only when
+// count is set since that reads every child directory
+func inspect(dir string, d os.DirEntry, count bool) Entry {
+	e := Entry{
+		Name:  d.Name(),
+		Path:  filepath.Join(dir, d.Name()),
+		IsDir: d.IsDir(),
+	}
+	if d.Type()&os.ModeSymlink != 0 {
+		e.Link, _ = os.Readlink(e.Path)
+		info, err := os.Stat(e.Path)
+		if err != nil {
+			e.Broken = true
+			return e
+		}
+		e.IsDir = info.IsDir()
+		e.ModTime, e.Size = info.ModTime(), info.Size()
+	} else if info, err := d.Info(); err == nil {
+		e.ModTime, e.Size = info.ModTime(), info.Size()
+	}
+	switch {
+	case e.IsDir && count:
+		n, ok := countEntries(e.Path)
+		e.Locked = !ok
+		e.Size = int64(n)
+	case e.IsDir:
+		e.Locked = !readable(e.Path)
+		e.Size = 0 // the size of the directory file itself means nothing here
+	}
+	return e
+}
+
+// readable reports whether a directory can be opened for listing
+func readable(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	f.Close()
+	return true
+}
+
+// countEntries counts a directory's children up to MaxCounted, reporting
+// false when it can't be opened for listing
+func countEntries(path string) (int, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+	names, _ := f.Readdirnames(MaxCounted)
+	return len(names), true
+}
+
+// Reason returns the short cause of a filesystem error, like
+// "permission denied", without the operation and path around it
+func Reason(err error) string {
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err.Error()
+	}
+	return err.Error()
+}
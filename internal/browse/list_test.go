@@ -0,0 +1,239 @@
+package browse
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// mkdirs creates directories and files below root; names ending in a slash
+// are directories
+func mkdirs(t *testing.T, root string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		path := filepath.Join(root, name)
+		if name[len(name)-1] == '/' {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// listAll reads a whole directory with opts, sorted
+func listAll(t *testing.T, path string, opts Options) []Entry {
+	t.Helper()
+	l, err := Open(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	var all []Entry
+	for {
+		page, err := l.Next(context.Background(), 2)
+		all = append(all, page...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	Sort(all, opts.Sort)
+	return all
+}
+
+func names(entries []Entry) []string {
+	var out []string
+	for _, e := range entries {
+		out = append(out, e.Name)
+	}
+	return out
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestListerOptions(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "b/", "a/one", "a/two", ".hidden/", "c/x", "file.txt", ".env")
+
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{"folders", Options{}, []string{"a", "b", "c"}},
+		{"files", Options{Files: true}, []string{"a", "b", "c", "file.txt"}},
+		{"hidden", Options{Hidden: true}, []string{".hidden", "a", "b", "c"}},
+		{"everything", Options{Files: true, Hidden: true}, []string{".hidden", "a", "b", "c", ".env", "file.txt"}},
+		{"by size", Options{Sort: SortSize}, []string{"a", "c", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(listAll(t, root, tt.opts)); !equal(got, tt.want) {
+				t.Errorf("listed %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListerCountsOnlyBySize(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "a/one", "a/two")
+
+	if got := listAll(t, root, Options{})[0].Size; got != 0 {
+		t.Errorf("counted %d children when sorting by name", got)
+	}
+	if got := listAll(t, root, Options{Sort: SortSize})[0].Size; got != 2 {
+		t.Errorf("counted %d children by size, want 2", got)
+	}
+}
+
+func TestListerLockedAndLinks(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "target/", "locked/")
+	if err := os.Symlink(filepath.Join(root, "target"), filepath.Join(root, "link")); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	os.Symlink(filepath.Join(root, "gone"), filepath.Join(root, "broken"))
+	if err := os.Chmod(filepath.Join(root, "locked"), 0); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(filepath.Join(root, "locked"), 0755)
+
+	byName := map[string]Entry{}
+	for _, e := range listAll(t, root, Options{}) {
+		byName[e.Name] = e
+	}
+	if e := byName["link"]; !e.IsDir || e.Link == "" || e.Broken {
+		t.Errorf("link = %+v, want a directory link", e)
+	}
+	if e, ok := byName["broken"]; !ok || !e.Broken {
+		t.Errorf("broken = %+v, want a broken link kept in the listing", e)
+	}
+	if os.Getuid() != 0 { // root opens anything
+		if e := byName["locked"]; !e.Locked {
+			t.Errorf("locked = %+v, want it marked locked", e)
+		}
+	}
+}
+
+func TestSortModes(t *testing.T) {
+	now := time.Now()
+	entries := []Entry{
+		{Name: "small.txt", Size: 10, ModTime: now.Add(-time.Hour)},
+		{Name: "b", IsDir: true, Size: 1, ModTime: now.Add(-2 * time.Hour)},
+		{Name: "big.txt", Size: 1000, ModTime: now.Add(-3 * time.Hour)},
+		{Name: "a", IsDir: true, Size: 5, ModTime: now},
+	}
+	tests := []struct {
+		mode SortMode
+		want []string
+	}{
+		{SortName, []string{"a", "b", "big.txt", "small.txt"}},
+		{SortModified, []string{"a", "b", "small.txt", "big.txt"}},
+		{SortSize, []string{"a", "b", "big.txt", "small.txt"}},
+	}
+	for _, tt := range tests {
+		sorted := append([]Entry(nil), entries...)
+		Sort(sorted, tt.mode)
+		if got := names(sorted); !equal(got, tt.want) {
+			t.Errorf("%s: %v, want %v", tt.mode, got, tt.want)
+		}
+	}
+	if SortSize.Next() != SortName || SortName.Next() != SortModified {
+		t.Error("Next does not cycle name, modified, size")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	entry := func(name string, dir bool) Entry { return Entry{Name: name, IsDir: dir} }
+	var sorted []Entry
+	pages := [][]Entry{
+		{entry("m", true), entry("b.txt", false)},
+		{},
+		{entry("z", true), entry("a", true), entry("a.txt", false)},
+		{entry("c", true)},
+	}
+	for _, page := range pages {
+		given := names(page)
+		sorted = Merge(sorted, page, SortName)
+		if !equal(names(page), given) {
+			t.Errorf("Merge reordered the page it was given: %v", names(page))
+		}
+	}
+	want := []string{"a", "c", "m", "z", "a.txt", "b.txt"}
+	if got := names(sorted); !equal(got, want) {
+		t.Errorf("Merge = %v, want %v", got, want)
+	}
+}
+
+func TestListerPages(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "a/", "b/", "c/", "d/", "e/")
+	l, err := Open(root, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	var all []Entry
+	for i := 0; ; i++ {
+		page, err := l.Next(context.Background(), 2)
+		if len(page) > 2 {
+			t.Fatalf("page of %d entries, want at most 2", len(page))
+		}
+		all = Merge(all, page, SortName)
+		if err == io.EOF {
+			break
+		}
+		if err != nil || i > 5 {
+			t.Fatalf("page %d: %v", i, err)
+		}
+	}
+	if got, want := names(all), []string{"a", "b", "c", "d", "e"}; !equal(got, want) {
+		t.Errorf("listed %v, want %v", got, want)
+	}
+	if _, err := l.Next(context.Background(), 2); err != io.EOF {
+		t.Errorf("Next after the end returned %v, want io.EOF", err)
+	}
+
+	if _, err := Open(filepath.Join(root, "missing"), Options{}); err == nil {
+		t.Error("Open of a missing directory should fail")
+	}
+}
+
+func TestListerCancelled(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "a/", "b/")
+	l, err := Open(root, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.Next(ctx, 10); err != context.Canceled {
+		t.Errorf("cancelled Next returned %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+// Package browse holds the filesystem logic behind the directory browser:
+// resolving typed paths and completing them like a shell does.
+package browse
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Expand resolves a typed path: ~ is the home directory and relative paths
+// are taken from cwd
+func Expand(input, cwd string) (string, error) {
+	path := strings.TrimSpace(input)
+	if path == "" {
+		return "", errors.New("type a path")
+	}
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, path[1:])
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(cwd, path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// Resolve expands input and checks that it is a directory that can be opened
+func Resolve(input, cwd string) (string, error) {
+	path, err := Expand(input, cwd)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		return "", fmt.Errorf("%s does not exist", Abbrev(path))
+	case err != nil:
+		return "", fmt.Errorf("%s: %w", Abbrev(path), errors.Unwrap(err))
+	case !info.IsDir():
+		return "", fmt.Errorf("%s is not a directory", Abbrev(path))
+	}
+	return path, nil
+}
+
+// Abbrev shortens the home directory to ~ for display and editing
+func Abbrev(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" || home == "/" {
+		return path
+	}
+	if path == home {
+		return "~"
+	}
+	if strings.HasPrefix(path, home+string(filepath.Separator)) {
+		return "~" + path[len(home):]
+	}
+	return path
+}
+
+// Complete completes the last element of input to the directories it can
+// name. The returned text keeps what was typed and extends it to the longest
+// common prefix of the candidates, with a trailing slash once only one is
+// left. Hidden directories are only offered when the prefix starts with a dot.
+func Complete(input, cwd string) (string, []string) {
+	if input == "~" {
+		return "~/", nil
+	}
+	dirText, prefix := "", input
+	if i := strings.LastIndex(input, "/"); i >= 0 {
+		dirText, prefix = input[:i+1], input[i+1:]
+	}
+
+	dir := cwd
+	if dirText != "" {
+		var err error
+		if dir, err = Expand(dirText, cwd); err != nil {
+			return input, nil
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return input, nil
+	}
+
+	var matches []string
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".") {
+			continue
+		}
+		if isDir(dir, e) {
+			matches = append(matches, name)
+		}
+	}
+	sort.Strings(matches)
+
+	switch len(matches) {
+	case 0:
+		return input, nil
+	case 1:
+		return dirText + matches[0] + "/", matches
+	}
+	return dirText + commonPrefix(matches), matches
+}
+
+// isDir reports whether an entry is a directory, following symlinks
+func isDir(dir string, e os.DirEntry) bool {
+	if e.IsDir() {
+		return true
+	}
+	if e.Type()&os.ModeSymlink == 0 {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(dir, e.Name()))
+	return err == nil && info.IsDir()
+}
+
+func commonPrefix(names []string) string {
+	prefix := []rune(names[0])
+	for _, name := range names[1:] {
+		n := 0
+		for _, r := range name {
+			if n == len(prefix) || prefix[n] != r {
+				break
+			}
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
@@ -0,0 +1,88 @@
+package browse
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	t.Setenv("HOME", "/home/me")
+	tests := []struct {
+		input, want string
+	}{
+		{"~", "/home/me"},
+		{"~/code/", "/home/me/code"},
+		{"  /tmp/x  ", "/tmp/x"},
+		{"app", "/work/app"},
+		{"../other", "/other"},
+		{"~user", "/work/~user"},
+	}
+	for _, tt := range tests {
+		got, err := Expand(tt.input, "/work")
+		if err != nil || got != tt.want {
+			t.Errorf("Expand(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+		}
+	}
+	if _, err := Expand("  ", "/work"); err == nil {
+		t.Error("Expand of a blank path should fail")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "app/", "notes.txt")
+	tests := []struct {
+		input, err string
+	}{
+		{"app", ""},
+		{root, ""},
+		{"missing", "does not exist"},
+		{"notes.txt", "is not a directory"},
+		{"", "type a path"},
+	}
+	for _, tt := range tests {
+		got, err := Resolve(tt.input, root)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("Resolve(%q) failed: %v", tt.input, err)
+		case tt.err == "" && !filepath.IsAbs(got):
+			t.Errorf("Resolve(%q) = %q, want an absolute path", tt.input, got)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("Resolve(%q) error = %v, want %q", tt.input, err, tt.err)
+		}
+	}
+}
+
+func TestComplete(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "apps/", "api/", "docs/", ".config/", "apple.txt", "docs/guide/")
+	if err := os.Symlink(filepath.Join(root, "docs"), filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", root)
+
+	tests := []struct {
+		input, want string
+		matches     []string
+	}{
+		{"ap", "ap", []string{"api", "apps"}},
+		{"app", "apps/", []string{"apps"}},
+		{"d", "docs/", []string{"docs"}},
+		{"docs/g", "docs/guide/", []string{"guide"}},
+		{"li", "link/", []string{"link"}},
+		{"", "", []string{"api", "apps", "docs", "link"}},
+		{".c", ".config/", []string{".config"}},
+		{"x", "x", nil},
+		{"missing/a", "missing/a", nil},
+		{"~", "~/", nil},
+		{"~/do", "~/docs/", []string{"docs"}},
+	}
+	for _, tt := range tests {
+		got, matches := Complete(tt.input, root)
+		if got != tt.want || !equal(matches, tt.matches) {
+			t.Errorf("Complete(%q) = %q %v, want %q %v", tt.input, got, matches, tt.want, tt.matches)
+		}
+	}
+}
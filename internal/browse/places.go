@@ -0,0 +1,164 @@
+package browse
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// maxRecent caps the history; the least used directories are dropped
+const maxRecent = 50
+
+// Visit is a parent directory a project was created in
+type Visit struct {
+	Path  string    `json:"path"`
+	Count int       `json:"count"`
+	Last  time.Time `json:"last"`
+}
+
+// Score ranks a visit by frecency: how often, weighted by how recently
+func (v Visit) Score(now time.Time) float64 {
+	age := now.Sub(v.Last)
+	weight := 0.25
+	switch {
+	case age < time.Hour:
+		weight = 4
+	case age < 24*time.Hour:
+		weight = 2
+	case age < 7*24*time.Hour:
+		weight = 1
+	case age < 30*24*time.Hour:
+		weight = 0.5
+	}
+	return float64(v.Count) * weight
+}
+
+// Places are the bookmarked and recently used parent directories
+type Places struct {
+	Bookmarks []string `json:"bookmarks"`
+	Recent    []Visit  `json:"recent"`
+
+	path string
+}
+
+// PlacesPath is where places are stored between runs
+func PlacesPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "nextui", "places.json")
+}
+
+// LoadPlaces reads the stored places; a missing file is an empty list
+func LoadPlaces() (*Places, error) {
+	p := &Places{path: PlacesPath()}
+	if p.path == "" {
+		return p, nil
+	}
+	data, err := os.ReadFile(p.path)
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+	if err != nil {
+		return p, err
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// Save writes the places back
+func (p *Places) Save() error {
+	if p.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(p.path, append(data, '\n'), 0644)
+}
+
+// Visit records that a project was created in dir
+func (p *Places) Visit(dir string, now time.Time) {
+	for i := range p.Recent {
+		if p.Recent[i].Path == dir {
+			p.Recent[i].Count++
+			p.Recent[i].Last = now
+			return
+		}
+	}
+	p.Recent = append(p.Recent, Visit{Path: dir, Count: 1, Last: now})
+	if len(p.Recent) > maxRecent {
+		sort.SliceStable(p.Recent, func(i, j int) bool {
+			return p.Recent[i].Score(now) > p.Recent[j].Score(now)
+		})
+		p.Recent = p.Recent[:maxRecent]
+	}
+}
+
+// Bookmarked reports whether dir is pinned
+func (p *Places) Bookmarked(dir string) bool {
+	for _, b := range p.Bookmarks {
+		if b == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleBookmark pins dir, or unpins it when it already is, and reports
+// whether it is pinned now
+func (p *Places) ToggleBookmark(dir string) bool {
+	for i, b := range p.Bookmarks {
+		if b == dir {
+			p.Bookmarks = append(p.Bookmarks[:i], p.Bookmarks[i+1:]...)
+			return false
+		}
+	}
+	p.Bookmarks = append(p.Bookmarks, dir)
+	return true
+}
+
+// Pick is an entry of the quick-pick list
+type Pick struct {
+	Path       string
+	Bookmarked bool
+}
+
+// Picks returns up to n places to offer: bookmarks in the order they were
+// pinned, then the most frecent recent directories. Directories that no
+// longer exist are left out.
+func (p *Places) Picks(now time.Time, n int) []Pick {
+	var picks []Pick
+	seen := map[string]bool{}
+	add := func(path string, bookmarked bool) {
+		if len(picks) == n || seen[path] {
+			return
+		}
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			return
+		}
+		seen[path] = true
+		picks = append(picks, Pick{Path: path, Bookmarked: bookmarked})
+	}
+
+	for _, b := range p.Bookmarks {
+		add(b, true)
+	}
+	recent := append([]Visit(nil), p.Recent...)
+	sort.SliceStable(recent, func(i, j int) bool {
+		return recent[i].Score(now) > recent[j].Score(now)
+	})
+	for _, v := range recent {
+		add(v.Path, false)
+	}
+	return picks
+}
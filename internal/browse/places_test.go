@@ -0,0 +1,101 @@
+package browse
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestVisitScore(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		age   time.Duration
+		count int
+		want  float64
+	}{
+		{time.Minute, 1, 4},
+		{2 * time.Hour, 3, 6},
+		{3 * 24 * time.Hour, 2, 2},
+		{10 * 24 * time.Hour, 4, 2},
+		{90 * 24 * time.Hour, 4, 1},
+	}
+	for _, tt := range tests {
+		v := Visit{Count: tt.count, Last: now.Add(-tt.age)}
+		if got := v.Score(now); got != tt.want {
+			t.Errorf("Score(%v old, %d visits) = %v, want %v", tt.age, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestVisit(t *testing.T) {
+	now := time.Now()
+	var p Places
+	p.Visit("/a", now.Add(-time.Hour))
+	p.Visit("/b", now.Add(-time.Hour))
+	p.Visit("/a", now)
+	if len(p.Recent) != 2 || p.Recent[0].Count != 2 || !p.Recent[0].Last.Equal(now) || p.Recent[1].Count != 1 {
+		t.Fatalf("Recent = %+v, want /a twice and /b once", p.Recent)
+	}
+
+	for i := 0; i < maxRecent; i++ {
+		p.Visit(fmt.Sprintf("/new%d", i), now)
+	}
+	if len(p.Recent) != maxRecent {
+		t.Fatalf("kept %d visits, want %d", len(p.Recent), maxRecent)
+	}
+	for _, v := range p.Recent {
+		if v.Path == "/b" {
+			t.Error("the least used directory should have been dropped")
+		}
+	}
+}
+
+func TestBookmarks(t *testing.T) {
+	var p Places
+	if !p.ToggleBookmark("/a") || !p.ToggleBookmark("/b") || !p.Bookmarked("/a") {
+		t.Fatal("pinning should report true")
+	}
+	if p.ToggleBookmark("/a") || p.Bookmarked("/a") || !p.Bookmarked("/b") {
+		t.Errorf("unpinning /a left %v", p.Bookmarks)
+	}
+}
+
+func TestPicks(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "pinned/", "other/", "often/", "once/", "old/", "file")
+	dir := func(name string) string { return filepath.Join(root, name) }
+	now := time.Now()
+	p := Places{
+		Bookmarks: []string{dir("pinned"), dir("gone"), dir("other")},
+		Recent: []Visit{
+			{Path: dir("once"), Count: 1, Last: now},
+			{Path: dir("often"), Count: 5, Last: now.Add(-2 * time.Hour)},
+			{Path: dir("old"), Count: 2, Last: now.Add(-60 * 24 * time.Hour)},
+			{Path: dir("pinned"), Count: 9, Last: now},
+			{Path: dir("file"), Count: 9, Last: now},
+		},
+	}
+
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{10, []string{"pinned*", "other*", "often", "once", "old"}},
+		{3, []string{"pinned*", "other*", "often"}},
+		{0, nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, pick := range p.Picks(now, tt.n) {
+			name := filepath.Base(pick.Path)
+			if pick.Bookmarked {
+				name += "*"
+			}
+			got = append(got, name)
+		}
+		if !equal(got, tt.want) {
+			t.Errorf("Picks(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
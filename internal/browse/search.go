@@ -0,0 +1,127 @@
+package browse
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sahilm/fuzzy"
+)
+
+const (
+	// SearchDepth is how many levels below the root a deep search looks
+	SearchDepth = 6
+	// maxWalked bounds how many directories one search visits
+	maxWalked = 50000
+)
+
+// skipDirs are never descended into, on top of hidden directories
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	".git":         true,
+}
+
+// Match is a directory found by Search
+type Match struct {
+	Path string
+	Rel  string // path relative to the search root, what the query matched
+	// Indexes are the byte offsets in Rel of the matched characters
+	Indexes []int
+}
+
+// entrySource lets fuzzy match entry names
+type entrySource []Entry
+
+func (s entrySource) String(i int) string { return s[i].Name }
+func (s entrySource) Len() int            { return len(s) }
+
+// Filter returns the entries whose name fuzzy-matches query, best match
+// first, with the matched characters recorded. An empty query keeps every
+// entry in its order.
+func Filter(query string, entries []Entry) []Entry {
+	if query == "" {
+		return entries
+	}
+	found := rank(query, fuzzy.FindFrom(query, entrySource(entries)))
+	filtered := make([]Entry, len(found))
+	for i, f := range found {
+		filtered[i] = entries[f.Index]
+		filtered[i].Matched = f.MatchedIndexes
+	}
+	return filtered
+}
+
+// rank re-sorts fuzzy matches so that names containing the query as typed
+// come first. fuzzy alone favours characters after separators, which puts
+// "a-long-pile-of-items" above "api" for the query "api". For paths only the
+// last element counts.
+func rank(query string, found fuzzy.Matches) fuzzy.Matches {
+	q := strings.ToLower(query)
+	score := func(m fuzzy.Match) int {
+		name := strings.ToLower(filepath.Base(m.Str))
+		switch {
+		case name == q:
+			return m.Score + 1000
+		case strings.HasPrefix(name, q):
+			return m.Score + 500
+		case strings.Contains(name, q):
+			return m.Score + 250
+		}
+		return m.Score
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return score(found[i]) > score(found[j])
+	})
+	return found
+}
+
+// Search walks the directories below root and returns up to limit of them
+// whose relative path fuzzy-matches query, best first. It stops early with
+// ctx's error when ctx is cancelled.
+func Search(ctx context.Context, root, query string, limit int) ([]Match, error) {
+	var rels []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if path == root {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if err != nil || strings.HasPrefix(d.Name(), ".") || skipDirs[d.Name()] {
+			// Unreadable, hidden and dependency directories are left out
+			return filepath.SkipDir
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		rels = append(rels, rel)
+		if len(rels) >= maxWalked {
+			return filepath.SkipAll
+		}
+		if strings.Count(rel, string(filepath.Separator))+1 >= SearchDepth {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	found := rank(query, fuzzy.Find(query, rels))
+	if len(found) > limit {
+		found = found[:limit]
+	}
+	matches := make([]Match, len(found))
+	for i, f := range found {
+		matches[i] = Match{
+			Path:    filepath.Join(root, f.Str),
+			Rel:     f.Str,
+			Indexes: f.MatchedIndexes,
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,114 @@
+package browse
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	var entries []Entry
+	for _, name := range []string{"a-long-pile-of-items", "my-api", "api", "apis", "docs", "Api-Docs"} {
+		entries = append(entries, Entry{Name: name})
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"a-long-pile-of-items", "my-api", "api", "apis", "docs", "Api-Docs"}},
+		{"api", []string{"api", "apis", "Api-Docs", "my-api", "a-long-pile-of-items"}},
+		{"docs", []string{"docs", "Api-Docs"}},
+		{"zzz", nil},
+	}
+	for _, tt := range tests {
+		if got := names(Filter(tt.query, entries)); !equal(got, tt.want) {
+			t.Errorf("Filter(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFilterMatchedOffsets(t *testing.T) {
+	entries := []Entry{{Name: "my-api"}, {Name: "café-app"}}
+	tests := []struct {
+		query string
+		name  string
+		want  []int
+	}{
+		{"api", "my-api", []int{3, 4, 5}},
+		// é is two bytes, so the offsets after it move by one
+		{"fap", "café-app", []int{2, 6, 7}},
+	}
+	for _, tt := range tests {
+		found := Filter(tt.query, entries)
+		if len(found) == 0 || found[0].Name != tt.name {
+			t.Fatalf("Filter(%q) = %v, want %s first", tt.query, names(found), tt.name)
+		}
+		got := found[0].Matched
+		if len(got) != len(tt.want) {
+			t.Fatalf("Filter(%q) matched %v, want %v", tt.query, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Filter(%q) matched %v, want %v", tt.query, got, tt.want)
+				break
+			}
+		}
+	}
+	if entries[0].Matched != nil {
+		t.Error("Filter changed the entries it was given")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root,
+		"web/app/api/",
+		"web/node_modules/api/",
+		".cache/api/",
+		"web/.git/api/",
+		"api.txt",
+		"a/b/c/d/e/api/",
+		"a/b/c/d/e/f/api/",
+	)
+	rels := func(matches []Match) []string {
+		var got []string
+		for _, m := range matches {
+			if m.Path != filepath.Join(root, m.Rel) {
+				t.Errorf("Path %s does not match Rel %s", m.Path, m.Rel)
+			}
+			got = append(got, m.Rel)
+		}
+		return got
+	}
+
+	matches, err := Search(context.Background(), root, "api", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := rels(matches)
+	want := []string{"a/b/c/d/e/api", "web/app/api"}
+	if !equal(got, want) {
+		t.Errorf("Search = %v, want %v; hidden, dependency and too deep directories are skipped", got, want)
+	}
+	for _, m := range matches {
+		if !strings.HasSuffix(m.Rel, "api") || len(m.Indexes) != 3 {
+			t.Errorf("%s matched at %v", m.Rel, m.Indexes)
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		mkdirs(t, root, fmt.Sprintf("more/api%d/", i))
+	}
+	if matches, _ = Search(context.Background(), root, "api", 3); len(matches) != 3 {
+		t.Errorf("limit 3 returned %d matches", len(matches))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Search(ctx, root, "api", 10); err != context.Canceled {
+		t.Errorf("cancelled search returned %v", err)
+	}
+}
@@ -0,0 +1,312 @@
+// Package devserver runs a generated project's dev server as a managed child
+// process: output is captured, the "Ready" line and port are detected and the
+// local URL is probed until it answers.
+package devserver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status is the lifecycle state of the server
+type Status int
+
+const (
+	StatusStopped Status = iota
+	StatusStarting
+	StatusReady   // Next.js printed its Ready line
+	StatusHealthy // the local URL answered 200
+	StatusExited  // the process ended on its own
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusStarting:
+		return "starting"
+	case StatusReady:
+		return "ready"
+	case StatusHealthy:
+		return "healthy"
+	case StatusExited:
+		return "exited"
+	}
+	return "stopped"
+}
+
+// maxOutput caps the captured output; older lines are dropped
+const maxOutput = 256 * 1024
+
+var (
+	// "✓ Ready in 1234ms" (Next 13+) or "ready - started server on ..." (Next 12)
+	readyRe = regexp.MustCompile(`(?i)\bready\b`)
+	// "- Local:        http://localhost:3000"
+	urlRe = regexp.MustCompile(`https?://(?:localhost|127\.0\.0\.1|0\.0\.0\.0|\[::1?\]):(\d+)`)
+)
+
+// Snapshot is a copy of the server state that is safe to read while it runs
+type Snapshot struct {
+	Status Status
+	Output string
+	Port   int
+	// Code is the HTTP status of the last probe, 0 until one completed
+	Code int
+	Err  error
+}
+
+// URL returns the local address once the port is known
+func (s Snapshot) URL() string {
+	if s.Port == 0 {
+		return ""
+	}
+	return fmt.Sprintf("http://localhost:%d", s.Port)
+}
+
+// Server manages a single dev server process
+type Server struct {
+	Dir  string
+	Name string
+	Args []string
+	Env  []string
+
+	// ProbeInterval and ProbeTimeout control the health check after Ready
+	ProbeInterval time.Duration
+	ProbeTimeout  time.Duration
+
+	mu      sync.Mutex
+	cmd     *exec.Cmd
+	done    chan struct{}
+	out     bytes.Buffer
+	partial string
+	status  Status
+	port    int
+	code    int
+	err     error
+	runID   int
+}
+
+// New returns a server running `npm run dev` in dir
+func New(dir string) *Server {
+	return &Server{
+		Dir:           dir,
+		Name:          "npm",
+		Args:          []string{"run", "dev"},
+		ProbeInterval: 500 * time.Millisecond,
+		ProbeTimeout:  2 * time.Minute, // the first request compiles the page
+	}
+}
+
+// Start launches the process. It is a no-op when the server already runs.
+func (s *Server) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cmd != nil {
+		return nil
+	}
+
+	cmd := exec.Command(s.Name, s.Args...)
+	cmd.Dir = s.Dir
+	cmd.Env = append(os.Environ(), s.Env...)
+	cmd.Env = append(cmd.Env, "FORCE_COLOR=0")
+	setProcessGroup(cmd)
+
+	s.runID++
+	w := &lineWriter{s: s, runID: s.runID}
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	s.out.Reset()
+	s.partial = ""
+	s.port = 0
+	s.code = 0
+	s.err = nil
+	fmt.Fprintf(&s.out, "$ %s %s\n", s.Name, strings.Join(s.Args, " "))
+
+	if err := cmd.Start(); err != nil {
+		s.status = StatusExited
+		s.err = err
+		return err
+	}
+	s.cmd = cmd
+	s.done = make(chan struct{})
+	s.status = StatusStarting
+
+	go s.wait(cmd, s.done, s.runID)
+	return nil
+}
+
+func (s *Server) wait(cmd *exec.Cmd, done chan struct{}, runID int) {
+	err := cmd.Wait()
+
+	s.mu.Lock()
+	if s.runID == runID {
+		s.cmd = nil
+		if s.status != StatusStopped {
+			s.status = StatusExited
+			s.err = err
+			if err == nil {
+				s.err = errors.New("dev server exited")
+			}
+		}
+	}
+	s.mu.Unlock()
+	close(done)
+}
+
+// Stop interrupts the process group and waits for it to exit, killing it
+// when it does not stop in time
+func (s *Server) Stop() error {
+	s.mu.Lock()
+	cmd, done := s.cmd, s.done
+	if cmd == nil {
+		s.mu.Unlock()
+		return nil
+	}
+	s.status = StatusStopped
+	s.mu.Unlock()
+
+	if err := interrupt(cmd); err != nil {
+		kill(cmd)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		kill(cmd)
+		<-done
+	}
+
+	s.mu.Lock()
+	s.out.WriteString("\n■ Dev server stopped\n")
+	s.mu.Unlock()
+	return nil
+}
+
+// Restart stops the server when it runs and starts it again
+func (s *Server) Restart() error {
+	if err := s.Stop(); err != nil {
+		return err
+	}
+	return s.Start()
+}
+
+// Running reports whether the process is alive
+func (s *Server) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cmd != nil
+}
+
+// Snapshot returns the current state and output
+func (s *Server) Snapshot() Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Snapshot{
+		Status: s.status,
+		Output: s.out.String() + s.partial,
+		Port:   s.port,
+		Code:   s.code,
+		Err:    s.err,
+	}
+}
+
+// line inspects a complete output line for the port and the Ready marker
+func (s *Server) line(runID int, line string) {
+	if s.runID != runID {
+		return
+	}
+	if m := urlRe.FindStringSubmatch(line); m != nil && s.port == 0 {
+		s.port, _ = strconv.Atoi(m[1])
+	}
+	if s.status == StatusStarting && readyRe.MatchString(line) {
+		s.status = StatusReady
+		port := s.port
+		if port == 0 {
+			port = 3000 // Next.js default when no Local line was printed
+			s.port = port
+		}
+		go s.probe(runID, port)
+	}
+}
+
+// probe polls the local URL until it answers 200, the server stops or the
+// timeout passes
+func (s *Server) probe(runID, port int) {
+	url := fmt.Sprintf("http://localhost:%d", port)
+	client := &http.Client{Timeout: s.ProbeTimeout}
+	deadline := time.Now().Add(s.ProbeTimeout)
+
+	for time.Now().Before(deadline) {
+		resp, err := client.Get(url)
+
+		s.mu.Lock()
+		if s.runID != runID || s.cmd == nil {
+			s.mu.Unlock()
+			if resp != nil {
+				resp.Body.Close()
+			}
+			return
+		}
+		if err == nil {
+			resp.Body.Close()
+			s.code = resp.StatusCode
+			if resp.StatusCode == http.StatusOK {
+				s.status = StatusHealthy
+				fmt.Fprintf(&s.out, "\n✓ %s answered 200 OK\n", url)
+				s.mu.Unlock()
+				return
+			}
+		}
+		s.mu.Unlock()
+
+		time.Sleep(s.ProbeInterval)
+	}
+
+	s.mu.Lock()
+	if s.runID == runID && s.cmd != nil {
+		fmt.Fprintf(&s.out, "\n⚠️  %s did not answer 200 within %s\n", url, s.ProbeTimeout)
+	}
+	s.mu.Unlock()
+}
+
+// lineWriter feeds process output into the server buffer line by line
+type lineWriter struct {
+	s     *Server
+	runID int
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	s := w.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.runID != w.runID {
+		return len(p), nil
+	}
+
+	data := s.partial + strings.ReplaceAll(string(p), "\r\n", "\n")
+	lines := strings.Split(data, "\n")
+	s.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		s.out.WriteString(line + "\n")
+		s.line(w.runID, line)
+	}
+
+	if s.out.Len() > maxOutput {
+		trimmed := s.out.Bytes()[s.out.Len()-maxOutput:]
+		if i := bytes.IndexByte(trimmed, '\n'); i >= 0 {
+			trimmed = trimmed[i+1:]
+		}
+		kept := append([]byte(nil), trimmed...)
+		s.out.Reset()
+		s.out.Write(kept)
+	}
+	return len(p), nil
+}
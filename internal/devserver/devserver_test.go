@@ -0,0 +1,94 @@
+package devserver
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectReadyAndPort(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		status Status
+		port   int
+	}{
+		{"starting", "> next dev --turbopack\n   ▲ Next.js 15.5.4\n", StatusStarting, 0},
+		{"next 15", "   - Local:        http://localhost:3001\n   - Network:      http://192.168.1.2:3001\n ✓ Ready in 812ms\n", StatusReady, 3001},
+		{"next 12", "ready - started server on 0.0.0.0:3000, url: http://localhost:3000\n", StatusReady, 3000},
+		{"ready without url", " ✓ Ready in 1s\n", StatusReady, 3000},
+		{"loopback", "- Local: http://127.0.0.1:4000\n", StatusStarting, 4000},
+		{"crlf and split writes", "- Local: http://localhost:5000\r\n ✓ Rea|dy in 1s\r\n", StatusReady, 5000},
+		{"partial line", " ✓ Ready in 1s", StatusStarting, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// No process runs, so the health probe gives up right away
+			s := &Server{status: StatusStarting, runID: 1, ProbeTimeout: 10 * time.Millisecond}
+			w := &lineWriter{s: s, runID: 1}
+			for _, chunk := range strings.Split(tt.output, "|") {
+				w.Write([]byte(chunk))
+			}
+			snap := s.Snapshot()
+			if snap.Status != tt.status || snap.Port != tt.port {
+				t.Errorf("status %v port %d, want %v and %d", snap.Status, snap.Port, tt.status, tt.port)
+			}
+			if got := strings.ReplaceAll(tt.output, "|", ""); snap.Output != strings.ReplaceAll(got, "\r\n", "\n") {
+				t.Errorf("output %q, want %q", snap.Output, got)
+			}
+		})
+	}
+}
+
+func TestStaleRunIgnored(t *testing.T) {
+	s := &Server{status: StatusStarting, runID: 2}
+	(&lineWriter{s: s, runID: 1}).Write([]byte("- Local: http://localhost:3000\n ✓ Ready\n"))
+	if snap := s.Snapshot(); snap.Status != StatusStarting || snap.Output != "" {
+		t.Errorf("output of an earlier run was kept: %+v", snap)
+	}
+}
+
+func TestServerBecomesHealthy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("needs sh")
+	}
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer site.Close()
+	u, _ := url.Parse(site.URL)
+
+	s := &Server{
+		Name:          "sh",
+		Args:          []string{"-c", fmt.Sprintf("echo '- Local: http://127.0.0.1:%s'; echo ' ✓ Ready in 5ms'; sleep 30", u.Port())},
+		ProbeInterval: 10 * time.Millisecond,
+		ProbeTimeout:  5 * time.Second,
+	}
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for s.Snapshot().Status != StatusHealthy {
+		if time.Now().After(deadline) {
+			t.Fatalf("server never became healthy: %+v", s.Snapshot())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if snap := s.Snapshot(); snap.Code != http.StatusOK || snap.URL() != "http://localhost:"+u.Port() {
+		t.Errorf("code %d url %s", snap.Code, snap.URL())
+	}
+
+	s.Stop()
+	if s.Running() || s.Snapshot().Status != StatusStopped {
+		t.Errorf("server still running after Stop: %+v", s.Snapshot())
+	}
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package devserver
+
+import (
+	"errors"
+	"os/exec"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// interrupt is not supported for console processes on Windows; Stop kills instead
+func interrupt(cmd *exec.Cmd) error {
+	return errors.New("interrupt not supported")
+}
+
+func kill(cmd *exec.Cmd) {
+	_ = cmd.Process.Kill()
+}
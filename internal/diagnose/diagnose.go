@@ -0,0 +1,171 @@
+// Package diagnose matches generation output against known failure
+// signatures and suggests fixes.
+package diagnose
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Signature is a known failure and what to do about it
+type Signature struct {
+	Name    string
+	Title   string
+	Pattern *regexp.Regexp
+	Fixes   []string
+	// Warning signatures are also shown when the run succeeded, because a
+	// fallback or optional command hid the failure
+	Warning bool
+}
+
+// Diagnosis is a signature found in the output, with the log lines around it
+type Diagnosis struct {
+	Signature
+	Line    int      // 1-based line of the last match
+	Excerpt []string // the matching line with some context
+}
+
+// excerptContext is how many lines to show on each side of a match
+const excerptContext = 2
+
+var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
+
+// Signatures are checked in order; the first ones are the most specific
+var Signatures = []Signature{
+	{
+		Name:    "tweakcn-404",
+		Title:   "Theme not found on tweakcn",
+		Pattern: regexp.MustCompile(`(?i)tweakcn\.com/r/themes/\S*.*(404|not found)|(404|not found).*tweakcn\.com/r/themes/`),
+		Fixes: []string{
+			"The theme was renamed or removed upstream: start another project and pick another theme",
+			"Open the theme URL from the log in a browser to check it still exists",
+			"Apply it later from the project with: npx shadcn add <theme URL from the log>",
+		},
+		Warning: true,
+	},
+	{
+		Name:    "eacces",
+		Title:   "Permission denied",
+		Pattern: regexp.MustCompile(`EACCES|EPERM|(?i)permission denied`),
+		Fixes: []string{
+			"Choose a parent directory you own, not one that needs sudo",
+			"If ~/.npm is owned by root, fix it with: sudo chown -R $(whoami) ~/.npm",
+			"Avoid sudo npm; install Node through nvm so global installs stay in your home directory",
+		},
+	},
+	{
+		Name:    "network",
+		Title:   "Network error while downloading packages",
+		Pattern: regexp.MustCompile(`ENOTFOUND|ETIMEDOUT|EAI_AGAIN|ECONNRESET|ECONNREFUSED|(?i)network request .* failed`),
+		Fixes: []string{
+			"Check your connection and that the registry answers: npm ping",
+			"Behind a proxy? Check npm config get proxy and npm config get https-proxy",
+			"Press r to retry the failed step once the network is back",
+		},
+	},
+	{
+		Name:    "eresolve",
+		Title:   "npm peer dependency conflict",
+		Pattern: regexp.MustCompile(`ERESOLVE|(?i)could not resolve dependency|conflicting peer dependency`),
+		Fixes: []string{
+			"Install the package by hand with: npm install --legacy-peer-deps",
+			"Or allow it for this project: echo \"legacy-peer-deps=true\" >> .npmrc, then press c to resume",
+			"Pin the package to a version compatible with the installed React/Next.js",
+		},
+	},
+	{
+		Name:    "engine",
+		Title:   "Unsupported Node.js version",
+		Pattern: regexp.MustCompile(`EBADENGINE|(?i)unsupported engine|requires node(\.js)? version|you are using node(\.js)? \S+.*required`),
+		Fixes: []string{
+			"Check your version with: node --version",
+			"Install and use Node.js 20 LTS: nvm install 20 && nvm use 20",
+			"Then press c to resume from the failed step",
+		},
+	},
+	{
+		Name:    "eexist",
+		Title:   "File or directory already exists",
+		Pattern: regexp.MustCompile(`EEXIST|(?i)already exists`),
+		Fixes: []string{
+			"Pick a different app name, or move the existing directory out of the way",
+			"If npm's cache is the culprit, clear it with: npm cache clean --force",
+		},
+	},
+	{
+		Name:    "missing-node",
+		Title:   "Node.js or npm is not installed",
+		Pattern: regexp.MustCompile(`(?i)missing required dependencies|node not found in path|npx: command not found|npm: command not found`),
+		Fixes: []string{
+			"Install Node.js 20 LTS from https://nodejs.org/ or with nvm",
+			"macOS: brew install node • Ubuntu/Debian: sudo apt install nodejs npm",
+		},
+	},
+	{
+		Name:    "git-identity",
+		Title:   "Git does not know who is committing",
+		Pattern: regexp.MustCompile(`(?i)please tell me who you are|unable to auto-detect email address`),
+		Fixes: []string{
+			"Pass an author for the scaffold commit: nextui --git-author \"Your Name <you@example.com>\"",
+			"Or set it once: git config --global user.name \"Your Name\" && git config --global user.email you@example.com, then press r",
+		},
+	},
+}
+
+// Diagnose returns every known failure signature found in output, the ones
+// closest to the end of the log (where the failure happened) first
+func Diagnose(output string) []Diagnosis {
+	lines := strings.Split(ansiRegex.ReplaceAllString(output, ""), "\n")
+
+	var found []Diagnosis
+	for _, sig := range Signatures {
+		// The last match is the one closest to the failure
+		last := -1
+		for i, line := range lines {
+			if sig.Pattern.MatchString(line) {
+				last = i
+			}
+		}
+		if last < 0 {
+			continue
+		}
+		found = append(found, Diagnosis{
+			Signature: sig,
+			Line:      last + 1,
+			Excerpt:   excerpt(lines, last),
+		})
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Line > found[j].Line
+	})
+	return found
+}
+
+// Warnings returns the diagnoses of Warning signatures, for a run that
+// succeeded with step warnings
+func Warnings(output string) []Diagnosis {
+	var found []Diagnosis
+	for _, d := range Diagnose(output) {
+		if d.Warning {
+			found = append(found, d)
+		}
+	}
+	return found
+}
+
+func excerpt(lines []string, at int) []string {
+	start := at - excerptContext
+	if start < 0 {
+		start = 0
+	}
+	end := at + excerptContext + 1
+	if end > len(lines) {
+		end = len(lines)
+	}
+	var out []string
+	for _, line := range lines[start:end] {
+		out = append(out, strings.TrimRight(line, " \r\t"))
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package diagnose
+
+import "testing"
+
+func TestDiagnose(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string // signature names, closest to the end first
+	}{
+		{"clean run", "npm install\nadded 200 packages\n", nil},
+		{"tweakcn 404", "$ npx shadcn add https://tweakcn.com/r/themes/gone.json\nError: https://tweakcn.com/r/themes/gone.json 404 Not Found\n", []string{"tweakcn-404"}},
+		{"permission", "npm ERR! code EACCES\nnpm ERR! syscall mkdir\n", []string{"eacces"}},
+		{"network", "npm ERR! code ENOTFOUND\nnpm ERR! network request to https://registry.npmjs.org failed\n", []string{"network"}},
+		{"peer conflict", "npm ERR! code ERESOLVE\nnpm ERR! ERESOLVE could not resolve\n", []string{"eresolve"}},
+		{"node version", "npm WARN EBADENGINE Unsupported engine\n", []string{"engine"}},
+		{"exists", "The directory myapp already exists\n", []string{"eexist"}},
+		{"missing node", "bash: npx: command not found\n", []string{"missing-node"}},
+		{"git identity", "*** Please tell me who you are.\n", []string{"git-identity"}},
+		{"colours", "\x1b[31mnpm ERR! code EACCES\x1b[0m\n", []string{"eacces"}},
+		{"latest first", "npm ERR! code EACCES\nlater\nnpm ERR! code ERESOLVE\n", []string{"eresolve", "eacces"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found := Diagnose(tt.output)
+			var got []string
+			for _, d := range found {
+				got = append(got, d.Name)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Diagnose = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Diagnose = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDiagnoseExcerpt(t *testing.T) {
+	output := "one\ntwo\nthree\nnpm ERR! code EACCES\nfive\nsix\nseven"
+	found := Diagnose(output)
+	if len(found) != 1 {
+		t.Fatalf("got %d diagnoses, want 1", len(found))
+	}
+	d := found[0]
+	if d.Line != 4 {
+		t.Errorf("Line = %d, want 4", d.Line)
+	}
+	want := []string{"two", "three", "npm ERR! code EACCES", "five", "six"}
+	if len(d.Excerpt) != len(want) {
+		t.Fatalf("Excerpt = %q, want %q", d.Excerpt, want)
+	}
+	for i := range want {
+		if d.Excerpt[i] != want[i] {
+			t.Errorf("Excerpt = %q, want %q", d.Excerpt, want)
+		}
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	output := "npm ERR! code EACCES\nError: https://tweakcn.com/r/themes/gone.json 404\n"
+	found := Warnings(output)
+	if len(found) != 1 || found[0].Name != "tweakcn-404" {
+		t.Errorf("Warnings = %v, want only tweakcn-404", found)
+	}
+}
@@ -0,0 +1,116 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DryRun runs and writes nothing; callers log each action as usual, so the
+// run output becomes a preview. Paths that commands or writes would create
+// are remembered so later existence checks behave as in a real run.
+type DryRun struct {
+	mu       sync.Mutex
+	commands int
+	written  map[string]bool
+	created  map[string]bool
+	removed  map[string]bool
+}
+
+// NewDryRun returns an executor that only pretends
+func NewDryRun() *DryRun {
+	return &DryRun{
+		written: map[string]bool{},
+		created: map[string]bool{},
+		removed: map[string]bool{},
+	}
+}
+
+func (d *DryRun) Run(c Command, stdout, stderr io.Writer) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.commands++
+	if c.Creates != "" {
+		d.mark(c.Creates)
+	}
+	return nil
+}
+
+func (d *DryRun) WriteFile(path string, data []byte, perm os.FileMode) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.written[filepath.Clean(path)] = true
+	d.mark(path)
+	return nil
+}
+
+// ReadFile reads the real file; content a dry run would have written is not
+// kept, so callers should handle a missing file
+func (d *DryRun) ReadFile(path string) ([]byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	path = filepath.Clean(path)
+	for p := range d.removed {
+		if under(path, p) {
+			return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+		}
+	}
+	return os.ReadFile(path)
+}
+
+func (d *DryRun) RemoveAll(path string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	path = filepath.Clean(path)
+	for p := range d.created {
+		if under(p, path) {
+			delete(d.created, p)
+		}
+	}
+	d.removed[path] = true
+	return nil
+}
+
+func (d *DryRun) Exists(path string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	path = filepath.Clean(path)
+	for p := range d.created {
+		if under(p, path) {
+			return true
+		}
+	}
+	for p := range d.removed {
+		if under(path, p) {
+			return false
+		}
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func (d *DryRun) LookPath(name string) (string, error) {
+	return exec.LookPath(name)
+}
+
+// Summary describes how much the dry run would have done
+func (d *DryRun) Summary() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return fmt.Sprintf("%d commands would run, %d files would be written", d.commands, len(d.written))
+}
+
+func (d *DryRun) mark(path string) {
+	path = filepath.Clean(path)
+	d.created[path] = true
+	delete(d.removed, path)
+}
+
+// under reports whether path is dir or inside it
+func under(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
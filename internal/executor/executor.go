@@ -0,0 +1,68 @@
+// Package executor runs external commands and touches the filesystem on
+// behalf of the generator, so runs can be previewed or recorded instead.
+package executor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Command is a single external program invocation
+type Command struct {
+	Dir      string   // working directory
+	Name     string   // program to run
+	Args     []string // arguments
+	Env      []string // extra KEY=value pairs on top of the current environment
+	Input    string   // piped to stdin
+	Yes      bool     // answer "y" to every prompt, like piping from `yes`
+	Creates  string   // path the command is expected to create, used by dry runs
+	Capture  string   // store trimmed stdout under this key (used by the step engine)
+	Optional bool     // failure becomes a warning instead of failing the step
+	Fallback []Command
+}
+
+// String renders the command the way it would be typed in a shell
+func (c Command) String() string {
+	parts := []string{c.Name}
+	for _, arg := range c.Args {
+		parts = append(parts, quote(arg))
+	}
+	line := strings.Join(parts, " ")
+	switch {
+	case c.Yes:
+		line = "yes | " + line
+	case c.Input != "" && len(c.Input) <= 32:
+		line = fmt.Sprintf("printf %q | %s", c.Input, line)
+	case c.Input != "":
+		line = fmt.Sprintf("%s < (%d bytes of input)", line, len(c.Input))
+	}
+	if c.Dir != "" {
+		line = fmt.Sprintf("(cd %s && %s)", quote(c.Dir), line)
+	}
+	return line
+}
+
+func quote(arg string) string {
+	if arg == "" || strings.ContainsAny(arg, " \t'\"$()[]*?&|;<>") {
+		return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return arg
+}
+
+// Executor is used for every command and file write the generator makes
+type Executor interface {
+	// Run executes the command, streaming its output to stdout and stderr.
+	Run(c Command, stdout, stderr io.Writer) error
+	// WriteFile writes data to path, creating parent directories.
+	WriteFile(path string, data []byte, perm os.FileMode) error
+	// ReadFile returns the content of path, used to patch generated files.
+	ReadFile(path string) ([]byte, error)
+	// RemoveAll deletes path and everything below it.
+	RemoveAll(path string) error
+	// Exists reports whether path exists (or would exist, for previews).
+	Exists(path string) bool
+	// LookPath searches PATH for an executable.
+	LookPath(name string) (string, error)
+}
@@ -0,0 +1,142 @@
+package executor
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommandString(t *testing.T) {
+	tests := []struct {
+		cmd  Command
+		want string
+	}{
+		{Command{Name: "npm", Args: []string{"install", "next"}}, "npm install next"},
+		{Command{Name: "git", Args: []string{"commit", "-m", "Initial scaffold"}}, "git commit -m 'Initial scaffold'"},
+		{Command{Name: "echo", Args: []string{"it's"}}, `echo 'it'\''s'`},
+		{Command{Name: "echo", Args: []string{""}}, "echo ''"},
+		{Command{Name: "npx", Args: []string{"shadcn", "add", "--all"}, Yes: true}, "yes | npx shadcn add --all"},
+		{Command{Name: "npx", Args: []string{"create-next-app"}, Input: "n\n"}, `printf "n\n" | npx create-next-app`},
+		{Command{Name: "cat", Input: strings.Repeat("x", 40)}, "cat < (40 bytes of input)"},
+		{Command{Name: "ls", Dir: "/my dir"}, "(cd '/my dir' && ls)"},
+	}
+	for _, tt := range tests {
+		if got := tt.cmd.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.txt")
+	if err := os.WriteFile(existing, []byte("real"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	project := filepath.Join(dir, "app")
+	d := NewDryRun()
+
+	if err := d.Run(Command{Name: "npx", Creates: project}, io.Discard, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.WriteFile(filepath.Join(project, "README.md"), []byte("# app"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	d.WriteFile(filepath.Join(project, "README.md"), []byte("# app"), 0644) // counted once
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{project, true},
+		{filepath.Join(project, "README.md"), true},
+		{filepath.Join(dir, "other"), false},
+		{existing, true},
+	}
+	for _, tt := range tests {
+		if got := d.Exists(tt.path); got != tt.want {
+			t.Errorf("Exists(%s) = %t, want %t", tt.path, got, tt.want)
+		}
+	}
+	if _, err := os.Stat(project); !os.IsNotExist(err) {
+		t.Errorf("dry run created %s", project)
+	}
+	if data, err := d.ReadFile(existing); err != nil || string(data) != "real" {
+		t.Errorf("ReadFile(existing) = %q, %v; want the real content", data, err)
+	}
+
+	d.RemoveAll(project)
+	d.RemoveAll(existing)
+	if d.Exists(project) || d.Exists(existing) {
+		t.Error("removed paths still exist")
+	}
+	if _, err := d.ReadFile(existing); !os.IsNotExist(err) {
+		t.Errorf("ReadFile after RemoveAll = %v, want not exist", err)
+	}
+	if _, err := os.Stat(existing); err != nil {
+		t.Errorf("dry run removed the real file: %v", err)
+	}
+
+	if got, want := d.Summary(), "1 commands would run, 1 files would be written"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
+
+func TestRecording(t *testing.T) {
+	r := &Recording{
+		Fail: func(c Command) error {
+			if c.Name == "broken" {
+				return errors.New("exit status 1")
+			}
+			return nil
+		},
+		Stdout:  func(c Command) string { return "out of " + c.Name },
+		Missing: []string{"bun"},
+	}
+
+	var out strings.Builder
+	if err := r.Run(Command{Name: "npx", Creates: "/work/app"}, &out, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "out of npx" {
+		t.Errorf("stdout = %q, want the Stdout result", out.String())
+	}
+	if err := r.Run(Command{Name: "broken"}, io.Discard, io.Discard); err == nil {
+		t.Error("broken command succeeded")
+	}
+	if got := len(r.Commands()); got != 2 {
+		t.Errorf("recorded %d commands, want 2 including the failed one", got)
+	}
+
+	r.WriteFile("/work/app/a.txt", []byte("a"), 0644)
+	if data, ok := r.File("/work/app/./a.txt"); !ok || string(data) != "a" {
+		t.Errorf("File = %q, %t; want the written content", data, ok)
+	}
+	if data, err := r.ReadFile("/work/app/a.txt"); err != nil || string(data) != "a" {
+		t.Errorf("ReadFile = %q, %v", data, err)
+	}
+	if _, err := r.ReadFile("/work/app/missing"); !os.IsNotExist(err) {
+		t.Errorf("ReadFile of a missing file = %v, want not exist", err)
+	}
+	if !r.Exists("/work/app") || !r.Exists("/work") || r.Exists("/elsewhere") {
+		t.Error("Exists does not follow created and written paths")
+	}
+
+	r.RemoveAll("/work/app")
+	if r.Exists("/work/app") || r.Exists("/work/app/a.txt") {
+		t.Error("RemoveAll kept paths inside the removed directory")
+	}
+	if got := r.Removed(); len(got) != 1 || got[0] != "/work/app" {
+		t.Errorf("Removed() = %v", got)
+	}
+
+	if _, err := r.LookPath("bun"); err == nil {
+		t.Error("LookPath found a missing executable")
+	}
+	if _, err := r.LookPath("npm"); err != nil {
+		t.Errorf("LookPath(npm) = %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package executor
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Real runs commands and writes files for real
+type Real struct{}
+
+func (Real) Run(c Command, stdout, stderr io.Writer) error {
+	cmd := exec.Command(c.Name, c.Args...)
+	cmd.Dir = c.Dir
+	cmd.Env = append(os.Environ(), c.Env...)
+	switch {
+	case c.Yes:
+		cmd.Stdin = &yesReader{}
+	case c.Input != "":
+		cmd.Stdin = strings.NewReader(c.Input)
+	}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+func (Real) WriteFile(path string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, perm)
+}
+
+func (Real) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func (Real) RemoveAll(path string) error {
+	return os.RemoveAll(path)
+}
+
+func (Real) Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func (Real) LookPath(name string) (string, error) {
+	return exec.LookPath(name)
+}
+
+// yesReader endlessly answers "y", like piping from the yes command
+type yesReader struct {
+	newline bool
+}
+
+func (y *yesReader) Read(p []byte) (int, error) {
+	for i := range p {
+		if y.newline {
+			p[i] = '\n'
+		} else {
+			p[i] = 'y'
+		}
+		y.newline = !y.newline
+	}
+	return len(p), nil
+}
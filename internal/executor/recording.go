@@ -0,0 +1,122 @@
+package executor
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Recording remembers every command and write without touching the system.
+// It is meant for tests driving the generator or the TUI.
+type Recording struct {
+	// Fail, when set, decides which commands fail.
+	Fail func(Command) error
+	// Stdout, when set, supplies the output a command prints.
+	Stdout func(Command) string
+	// Missing lists executables LookPath should not find.
+	Missing []string
+
+	mu       sync.Mutex
+	commands []Command
+	files    map[string][]byte
+	removed  []string
+}
+
+func (r *Recording) Run(c Command, stdout, stderr io.Writer) error {
+	r.mu.Lock()
+	r.commands = append(r.commands, c)
+	if c.Creates != "" {
+		r.ensureFiles()
+		r.files[filepath.Clean(c.Creates)] = nil
+	}
+	r.mu.Unlock()
+
+	if r.Stdout != nil {
+		io.WriteString(stdout, r.Stdout(c))
+	}
+	if r.Fail != nil {
+		return r.Fail(c)
+	}
+	return nil
+}
+
+func (r *Recording) WriteFile(path string, data []byte, perm os.FileMode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ensureFiles()
+	r.files[filepath.Clean(path)] = append([]byte(nil), data...)
+	return nil
+}
+
+func (r *Recording) ReadFile(path string) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, ok := r.files[filepath.Clean(path)]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+	}
+	return append([]byte(nil), data...), nil
+}
+
+func (r *Recording) RemoveAll(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	path = filepath.Clean(path)
+	r.removed = append(r.removed, path)
+	for p := range r.files {
+		if under(p, path) {
+			delete(r.files, p)
+		}
+	}
+	return nil
+}
+
+func (r *Recording) Exists(path string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	path = filepath.Clean(path)
+	for p := range r.files {
+		if under(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Recording) LookPath(name string) (string, error) {
+	for _, m := range r.Missing {
+		if m == name {
+			return "", &os.PathError{Op: "lookpath", Path: name, Err: os.ErrNotExist}
+		}
+	}
+	return "/usr/bin/" + name, nil
+}
+
+// Commands returns the commands run so far
+func (r *Recording) Commands() []Command {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Command(nil), r.commands...)
+}
+
+// File returns the content written to path and whether it was written
+func (r *Recording) File(path string) ([]byte, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, ok := r.files[filepath.Clean(path)]
+	return data, ok
+}
+
+// Removed returns the paths passed to RemoveAll
+func (r *Recording) Removed() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.removed...)
+}
+
+func (r *Recording) ensureFiles() {
+	if r.files == nil {
+		r.files = map[string][]byte{}
+	}
+}
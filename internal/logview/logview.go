@@ -0,0 +1,91 @@
+// Package logview prepares command output for the output viewport: it keeps
+// the colours tools print, drops the escapes that only make sense on a real
+// terminal, and finds and highlights search matches.
+package logview
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// escapeRegex matches CSI sequences (colours end in m) and OSC sequences
+// such as window titles and hyperlinks
+var escapeRegex = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// Clean keeps colour sequences and removes cursor movement, line erasing and
+// other control sequences. Spinners and progress bars redraw a line with a
+// carriage return, so only the text after the last one is kept.
+func Clean(output string) string {
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+	output = escapeRegex.ReplaceAllStringFunc(output, func(seq string) string {
+		if strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m") {
+			return seq
+		}
+		return ""
+	})
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		if j := strings.LastIndex(line, "\r"); j >= 0 {
+			lines[i] = line[j+1:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Plain removes every escape sequence, for the clipboard
+func Plain(output string) string {
+	return ansi.Strip(output)
+}
+
+// Find returns the indexes of the lines of content containing query,
+// ignoring case and colours
+func Find(content, query string) []int {
+	if query == "" {
+		return nil
+	}
+	q := strings.ToLower(query)
+	var matches []int
+	for i, line := range strings.Split(content, "\n") {
+		if strings.Contains(strings.ToLower(ansi.Strip(line)), q) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// Highlight marks every occurrence of query in the matching lines of
+// content, using current for the line at index active. Matching lines lose
+// their own colours so the marks stand out.
+func Highlight(content, query string, matches []int, active int, hit, current lipgloss.Style) string {
+	if len(matches) == 0 {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	q := strings.ToLower(query)
+	for _, i := range matches {
+		style := hit
+		if i == active {
+			style = current
+		}
+		plain := ansi.Strip(lines[i])
+		lower := strings.ToLower(plain)
+		var b strings.Builder
+		for {
+			// Lowercasing can change byte lengths outside ASCII, so fall
+			// back to the plain line rather than cut a rune in half
+			j := strings.Index(lower, q)
+			if j < 0 || len(lower) != len(plain) {
+				b.WriteString(plain)
+				break
+			}
+			b.WriteString(plain[:j])
+			b.WriteString(style.Render(plain[j : j+len(q)]))
+			plain, lower = plain[j+len(q):], lower[j+len(q):]
+		}
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
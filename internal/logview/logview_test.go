@@ -0,0 +1,83 @@
+package logview
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"plain", "hello\nworld", "hello\nworld"},
+		{"colours kept", "\x1b[32m✓\x1b[0m done", "\x1b[32m✓\x1b[0m done"},
+		{"cursor and erase dropped", "\x1b[2K\x1b[1Gline\x1b[?25l", "line"},
+		{"window title dropped", "\x1b]0;npm install\x07ok", "ok"},
+		{"hyperlink dropped", "\x1b]8;;https://x.dev\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"spinner redraws", "⠋ installing\r⠙ installing\rinstalled\nnext", "installed\nnext"},
+		{"crlf", "one\r\ntwo\r\n", "one\ntwo\n"},
+	}
+	for _, tt := range tests {
+		if got := Clean(tt.in); got != tt.want {
+			t.Errorf("%s: Clean(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPlain(t *testing.T) {
+	if got := Plain("\x1b[1;31merror\x1b[0m: boom"); got != "error: boom" {
+		t.Errorf("Plain = %q", got)
+	}
+}
+
+func TestFind(t *testing.T) {
+	content := "Compiled\n\x1b[31mERR\x1b[0mOR in app\nno errors\n\nwarning"
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"error", []int{1, 2}},
+		{"WARN", []int{4}},
+		{"31m", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got := Find(content, tt.query)
+		if len(got) != len(tt.want) {
+			t.Errorf("Find(%q) = %v, want %v", tt.query, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Find(%q) = %v, want %v", tt.query, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	hit := lipgloss.NewStyle().Transform(func(s string) string { return "[" + s + "]" })
+	current := lipgloss.NewStyle().Transform(func(s string) string { return "<" + s + ">" })
+
+	tests := []struct {
+		name, content, query string
+		active               int
+		want                 string
+	}{
+		{"every occurrence", "Error: error\nfine", "error", -1, "[Error]: [error]\nfine"},
+		{"active line", "a err\nb err", "err", 1, "a [err]\nb <err>"},
+		{"colours dropped", "\x1b[31mfail\x1b[0m here", "fail", -1, "[fail] here"},
+		{"lowercase changes length", "İstanbul fail", "fail", -1, "İstanbul fail"},
+	}
+	for _, tt := range tests {
+		got := Highlight(tt.content, tt.query, Find(tt.content, tt.query), tt.active, hit, current)
+		if got != tt.want {
+			t.Errorf("%s: Highlight = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if got := Highlight("a\nb", "x", nil, 0, hit, current); got != "a\nb" {
+		t.Errorf("Highlight without matches = %q", got)
+	}
+}
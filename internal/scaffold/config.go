@@ -0,0 +1,257 @@
+package scaffold
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// NodeVersion is the Node.js major version projects are generated and built with
+const NodeVersion = "20"
+
+// Auth selects which authentication setup gets generated
+type Auth string
+
+const (
+	AuthNone       Auth = "none"
+	AuthClerk      Auth = "clerk"
+	AuthBetterAuth Auth = "better-auth"
+)
+
+// Tooling selects the linter and formatter set up in the project
+type Tooling string
+
+const (
+	ToolingESLintPrettier Tooling = "eslint-prettier"
+	ToolingBiome          Tooling = "biome"
+)
+
+// NextOptions are the create-next-app choices. The zero value is the
+// original layout: TypeScript, App Router, src/, Turbopack and "@/*".
+type NextOptions struct {
+	JavaScript    bool   `json:"javascript"`
+	PagesRouter   bool   `json:"pagesRouter"`
+	NoSrcDir      bool   `json:"noSrcDir"`
+	NoTurbopack   bool   `json:"noTurbopack"`
+	ImportAlias   string `json:"importAlias"` // e.g. "~/*", empty for "@/*"
+	ReactCompiler bool   `json:"reactCompiler"`
+}
+
+// DefaultImportAlias is the alias create-next-app configures by default
+const DefaultImportAlias = "@/*"
+
+// ClerkOptions configures the richer Clerk scaffold (middleware, dashboard, header)
+type ClerkOptions struct {
+	Scaffold        bool     `json:"scaffold"`
+	PublicRoutes    []string `json:"publicRoutes"`
+	ProtectedRoutes []string `json:"protectedRoutes"`
+	Organizations   bool     `json:"organizations"`
+}
+
+// GitOptions configures the initial commit and where it is pushed
+type GitOptions struct {
+	Skip    bool   `json:"skip"`
+	Author  string `json:"author"`  // "Name <email>", empty for the git config identity
+	Message string `json:"message"` // defaults to DefaultCommitMessage
+	Remote  string `json:"remote"`  // added as origin when set
+	Push    bool   `json:"push"`
+}
+
+// DefaultCommitMessage is the subject of the single scaffold commit
+const DefaultCommitMessage = "Initial scaffold"
+
+// CommitMessage returns the configured message or the default one
+func (g GitOptions) CommitMessage() string {
+	if strings.TrimSpace(g.Message) == "" {
+		return DefaultCommitMessage
+	}
+	return g.Message
+}
+
+// AuthorEnv turns Author into git's author and committer variables
+func (g GitOptions) AuthorEnv() ([]string, error) {
+	if strings.TrimSpace(g.Author) == "" {
+		return nil, nil
+	}
+	open := strings.LastIndex(g.Author, "<")
+	if open < 0 || !strings.HasSuffix(strings.TrimSpace(g.Author), ">") {
+		return nil, fmt.Errorf("git author must look like \"Name <email>\": %q", g.Author)
+	}
+	name := strings.TrimSpace(g.Author[:open])
+	email := strings.TrimSuffix(strings.TrimSpace(g.Author[open+1:]), ">")
+	if name == "" || email == "" {
+		return nil, fmt.Errorf("git author must look like \"Name <email>\": %q", g.Author)
+	}
+	return []string{
+		"GIT_AUTHOR_NAME=" + name,
+		"GIT_AUTHOR_EMAIL=" + email,
+		"GIT_COMMITTER_NAME=" + name,
+		"GIT_COMMITTER_EMAIL=" + email,
+	}, nil
+}
+
+// CIOptions selects the CI workflows written into the project
+type CIOptions struct {
+	GitHub bool `json:"github"` // .github/workflows/ci.yml
+	GitLab bool `json:"gitlab"` // .gitlab-ci.yml
+}
+
+// TestingOptions selects the test tooling set up in the project
+type TestingOptions struct {
+	Vitest     bool `json:"vitest"`     // Vitest + React Testing Library
+	Playwright bool `json:"playwright"` // Playwright end-to-end tests
+}
+
+// Config holds everything the wizard collected for one project
+type Config struct {
+	AppName   string         `json:"appName"`
+	ParentDir string         `json:"parentDir"`
+	Theme     string         `json:"theme"` // tweakcn theme name, empty for the default shadcn theme
+	Auth      Auth           `json:"auth"`
+	Clerk     ClerkOptions   `json:"clerk"`
+	Verify    bool           `json:"verify"` // typecheck, lint and build once generated
+	Git       GitOptions     `json:"git"`
+	Docker    bool           `json:"docker"` // Dockerfile, .dockerignore and docker-compose.yml
+	CI        CIOptions      `json:"ci"`
+	Testing   TestingOptions `json:"testing"`
+	Next      NextOptions    `json:"next"`
+	Tooling   Tooling        `json:"tooling"`  // empty keeps create-next-app's plain ESLint
+	GitHooks  bool           `json:"gitHooks"` // husky + lint-staged pre-commit hook
+	Versions  Profile        `json:"versions"` // pinned package versions, recorded so resume uses the same ones
+}
+
+// ProjectName returns the app name the way create-next-app will see it
+func (c Config) ProjectName() string {
+	return strings.ReplaceAll(strings.ToLower(c.AppName), " ", "-")
+}
+
+// ProjectDir returns the full path of the generated project
+func (c Config) ProjectDir() string {
+	return filepath.Join(c.ParentDir, c.ProjectName())
+}
+
+// SplitRoutes turns a comma separated route list into trimmed entries
+func SplitRoutes(list string) []string {
+	var routes []string
+	for _, route := range strings.Split(list, ",") {
+		route = strings.TrimSpace(route)
+		if route != "" {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// Pkg returns the pinned npm spec for a package, like "shadcn@3.3.1"
+func (c Config) Pkg(name string) string {
+	return c.Versions.Pin(name)
+}
+
+// Pkgs pins every package in names
+func (c Config) Pkgs(names ...string) []string {
+	specs := make([]string, len(names))
+	for i, name := range names {
+		specs[i] = c.Pkg(name)
+	}
+	return specs
+}
+
+// Validate reports combinations the generator cannot produce
+func (c Config) Validate() error {
+	if c.Auth == AuthClerk && c.Clerk.Scaffold && c.Next.PagesRouter {
+		return fmt.Errorf("Clerk + Protected Routes needs the App Router")
+	}
+	if c.GitHooks && c.Git.Skip {
+		// husky installs into .git, which --no-git asked not to create
+		return fmt.Errorf("Git hooks need a git repository: untick them or drop --no-git")
+	}
+	alias := c.ImportAlias()
+	if !strings.HasSuffix(alias, "/*") || strings.ContainsAny(alias, " \"'") || len(alias) < 3 {
+		return fmt.Errorf("import alias must look like @/* or ~/*: %q", alias)
+	}
+	return nil
+}
+
+// TypeScript reports whether the project is written in TypeScript
+func (c Config) TypeScript() bool {
+	return !c.Next.JavaScript
+}
+
+// AppRouter reports whether the project uses the App Router
+func (c Config) AppRouter() bool {
+	return !c.Next.PagesRouter
+}
+
+// ImportAlias returns the tsconfig path alias, like "@/*"
+func (c Config) ImportAlias() string {
+	if c.Next.ImportAlias == "" {
+		return DefaultImportAlias
+	}
+	return c.Next.ImportAlias
+}
+
+// Alias returns the import prefix generated files use, like "@/"
+func (c Config) Alias() string {
+	return strings.TrimSuffix(c.ImportAlias(), "*")
+}
+
+// SrcPath returns a source path relative to the project, inside src/ when used
+func (c Config) SrcPath(rel string) string {
+	if c.Next.NoSrcDir {
+		return rel
+	}
+	return "src/" + rel
+}
+
+// ScriptExt is the extension of plain modules: ts or js
+func (c Config) ScriptExt() string {
+	if c.Next.JavaScript {
+		return "js"
+	}
+	return "ts"
+}
+
+// ComponentExt is the extension of React components: tsx or jsx
+func (c Config) ComponentExt() string {
+	if c.Next.JavaScript {
+		return "jsx"
+	}
+	return "tsx"
+}
+
+// NextConfigFile is the name of the config file create-next-app writes
+func (c Config) NextConfigFile() string {
+	if c.Next.JavaScript {
+		return "next.config.mjs"
+	}
+	return "next.config.ts"
+}
+
+// GlobalsCSS is where create-next-app puts the Tailwind stylesheet
+func (c Config) GlobalsCSS() string {
+	if c.Next.PagesRouter {
+		return c.SrcPath("styles/globals.css")
+	}
+	return c.SrcPath("app/globals.css")
+}
+
+// Layout describes the create-next-app choices in one line
+func (c Config) Layout() string {
+	parts := []string{"TypeScript", "App Router", "src/", "Turbopack", c.ImportAlias()}
+	if c.Next.JavaScript {
+		parts[0] = "JavaScript"
+	}
+	if c.Next.PagesRouter {
+		parts[1] = "Pages Router"
+	}
+	if c.Next.NoSrcDir {
+		parts[2] = "no src/"
+	}
+	if c.Next.NoTurbopack {
+		parts[3] = "Webpack"
+	}
+	if c.Next.ReactCompiler {
+		parts = append(parts, "React Compiler")
+	}
+	return strings.Join(parts, " · ")
+}
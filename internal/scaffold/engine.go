@@ -0,0 +1,335 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/WillyV3/nextjs-templater/internal/executor"
+	"github.com/WillyV3/nextjs-templater/internal/verify"
+)
+
+// Status is the state of a single step in a run
+type Status int
+
+const (
+	StatusPending Status = iota
+	StatusRunning
+	StatusDone
+	StatusSkipped
+	StatusFailed
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusRunning:
+		return "running"
+	case StatusDone:
+		return "done"
+	case StatusSkipped:
+		return "skipped"
+	case StatusFailed:
+		return "failed"
+	}
+	return "pending"
+}
+
+// StepResult is reported to the caller every time a step changes state
+type StepResult struct {
+	Name     string
+	Title    string
+	Status   Status
+	Duration time.Duration
+	Err      error
+	Warnings []string
+}
+
+// Command is a single external program invocation
+type Command = executor.Command
+
+// File is a file written by a step, relative to the project directory
+type File struct {
+	Path    string
+	Content string
+	Mode    os.FileMode // defaults to 0644
+}
+
+// Step is one phase of project generation
+type Step struct {
+	Name  string
+	Title string
+	// Repeat steps run again on resume even when an earlier run completed them.
+	Repeat bool
+
+	// When reports whether the step applies to the config. Nil means always.
+	When func(Config) bool
+	// Pre checks preconditions before anything runs. When it fails the step
+	// never started, so Rollback is not called. Returning an error wrapping
+	// ErrSkip skips the step instead of failing the run.
+	Pre func(*Context) error
+	// Commands returns the commands to run, in order.
+	Commands func(*Context) []Command
+	// Files returns files to write once the commands succeed.
+	Files func(*Context) ([]File, error)
+	// Check runs last and fails the step when the result is not usable.
+	Check func(*Context) error
+	// Rollback undoes the step's side effects after a failure once Pre passed.
+	Rollback func(*Context) error
+}
+
+// ErrSkip is wrapped by Pre when the step doesn't apply on this machine,
+// e.g. because an optional tool isn't installed
+var ErrSkip = errors.New("skipped")
+
+// Context is shared by every step of a run
+type Context struct {
+	Config Config
+	Out    io.Writer
+	Exec   executor.Executor
+
+	// Verification holds the results of the verify step, if it ran
+	Verification []verify.Result
+
+	env  []string
+	vars map[string]string
+}
+
+// NewContext creates a run context writing command output to out and
+// performing every action through exec
+func NewContext(cfg Config, out io.Writer, exec executor.Executor) *Context {
+	return &Context{
+		Config: cfg,
+		Out:    out,
+		Exec:   exec,
+		vars:   map[string]string{},
+	}
+}
+
+// Var returns a value captured by an earlier command
+func (c *Context) Var(key string) string {
+	return c.vars[key]
+}
+
+// SetEnv adds an environment variable for every following command
+func (c *Context) SetEnv(key, value string) {
+	c.env = append(c.env, key+"="+value)
+}
+
+// Logf writes a progress line to the run output
+func (c *Context) Logf(format string, args ...any) {
+	fmt.Fprintf(c.Out, format+"\n", args...)
+}
+
+// Engine runs steps in order and reports their results. Progress is
+// recorded in StateFile inside the project so a failed run can resume.
+type Engine struct {
+	Steps    []Step
+	OnResult func(StepResult)
+
+	// Completed lists steps finished by an earlier run; they are skipped.
+	Completed []string
+	// Until stops the run after the named step, e.g. to retry only that step.
+	Until string
+}
+
+// Plan returns the steps that apply to the config, in run order
+func (e *Engine) Plan(cfg Config) []Step {
+	var steps []Step
+	for _, s := range e.Steps {
+		if s.When == nil || s.When(cfg) {
+			steps = append(steps, s)
+		}
+	}
+	return steps
+}
+
+// Run executes every applicable step. It stops at the first failing step,
+// rolls that step back if it got past Pre and returns its error; earlier
+// steps are kept.
+func (e *Engine) Run(ctx *Context) ([]StepResult, error) {
+	if err := ctx.Config.Validate(); err != nil {
+		return nil, err
+	}
+	plan := e.Plan(ctx.Config)
+	results := make([]StepResult, len(plan))
+	for i, s := range plan {
+		results[i] = StepResult{Name: s.Name, Title: s.Title, Status: StatusPending}
+	}
+
+	state := &State{Completed: append([]string(nil), e.Completed...)}
+
+	for i, s := range plan {
+		if state.IsCompleted(s.Name) && !s.Repeat {
+			results[i].Status = StatusSkipped
+			ctx.Logf("\n↷ %s (already completed)", s.Title)
+			e.report(results[i])
+			continue
+		}
+
+		results[i].Status = StatusRunning
+		e.report(results[i])
+		ctx.Logf("\n▶ %s", s.Title)
+
+		start := time.Now()
+		warnings, started, err := e.runStep(ctx, s)
+		results[i].Duration = time.Since(start)
+		results[i].Warnings = warnings
+
+		if !started && errors.Is(err, ErrSkip) {
+			results[i].Status = StatusSkipped
+			ctx.Logf("↷ %s (%v)", s.Title, err)
+			e.report(results[i])
+			if s.Name == e.Until {
+				break
+			}
+			continue
+		}
+
+		if err != nil {
+			results[i].Status = StatusFailed
+			results[i].Err = err
+			ctx.Logf("❌ %s failed: %v", s.Title, err)
+			if started && s.Rollback != nil {
+				if rbErr := s.Rollback(ctx); rbErr != nil {
+					ctx.Logf("⚠️  Rollback of %s failed: %v", s.Name, rbErr)
+				}
+			}
+			state.Failed = s.Name
+			state.Error = err.Error()
+			// Before any step finished, the project directory (if any) is
+			// not ours to write to
+			if started || len(state.Completed) > 0 {
+				if err := state.save(ctx); err != nil {
+					ctx.Logf("⚠️  Could not record progress: %v", err)
+				}
+			}
+			e.report(results[i])
+			return results, fmt.Errorf("%s: %w", s.Name, err)
+		}
+
+		results[i].Status = StatusDone
+		ctx.Logf("✅ %s", s.Title)
+		if !state.IsCompleted(s.Name) {
+			state.Completed = append(state.Completed, s.Name)
+		}
+		state.Failed = ""
+		state.Error = ""
+		if err := state.save(ctx); err != nil {
+			ctx.Logf("⚠️  Could not record progress: %v", err)
+		}
+		e.report(results[i])
+
+		if s.Name == e.Until {
+			// Leave the remaining steps pending
+			break
+		}
+	}
+
+	return results, nil
+}
+
+func (e *Engine) report(r StepResult) {
+	if e.OnResult != nil {
+		e.OnResult(r)
+	}
+}
+
+// runStep runs one step, reporting whether it got past Pre
+func (e *Engine) runStep(ctx *Context, s Step) ([]string, bool, error) {
+	if s.Pre != nil {
+		if err := s.Pre(ctx); err != nil {
+			return nil, false, err
+		}
+	}
+	warnings, err := e.runActions(ctx, s)
+	return warnings, true, err
+}
+
+// runActions runs the commands, files and check of a step
+func (e *Engine) runActions(ctx *Context, s Step) ([]string, error) {
+	var warnings []string
+
+	if s.Commands != nil {
+		for _, c := range s.Commands(ctx) {
+			err := runCommand(ctx, c)
+			if err != nil && len(c.Fallback) > 0 {
+				ctx.Logf("⚠️  %s failed, falling back...", c.Name)
+				warnings = append(warnings, fmt.Sprintf("%s: %v, fell back to %s", c, err, c.Fallback[0]))
+				err = nil
+				for _, fb := range c.Fallback {
+					if err = runCommand(ctx, fb); err != nil {
+						break
+					}
+				}
+			}
+			if err != nil {
+				if c.Optional {
+					warnings = append(warnings, fmt.Sprintf("%s: %v", c, err))
+					ctx.Logf("⚠️  %s failed, but continuing...", c)
+					continue
+				}
+				return warnings, fmt.Errorf("%s: %w", c, err)
+			}
+		}
+	}
+
+	if s.Files != nil {
+		files, err := s.Files(ctx)
+		if err != nil {
+			return warnings, err
+		}
+		for _, f := range files {
+			if err := writeFile(ctx, f); err != nil {
+				return warnings, err
+			}
+		}
+	}
+
+	if s.Check != nil {
+		if err := s.Check(ctx); err != nil {
+			return warnings, err
+		}
+	}
+
+	return warnings, nil
+}
+
+func runCommand(ctx *Context, c Command) error {
+	logged := c
+	if c.Dir == "" || c.Dir == ctx.Config.ProjectDir() {
+		c.Dir = ctx.Config.ProjectDir()
+		logged.Dir = ""
+	}
+	c.Env = append(append([]string(nil), ctx.env...), c.Env...)
+	ctx.Logf("$ %s", logged)
+
+	var captured bytes.Buffer
+	stdout := ctx.Out
+	if c.Capture != "" {
+		stdout = &captured
+		// Captured values are used as they are, so no colours
+		c.Env = append(c.Env, "FORCE_COLOR=0", "NO_COLOR=1")
+	}
+
+	if err := ctx.Exec.Run(c, stdout, ctx.Out); err != nil {
+		return err
+	}
+	if c.Capture != "" {
+		ctx.vars[c.Capture] = strings.TrimSpace(captured.String())
+	}
+	return nil
+}
+
+func writeFile(ctx *Context, f File) error {
+	mode := f.Mode
+	if mode == 0 {
+		mode = 0644
+	}
+	ctx.Logf("write %s", f.Path)
+	return ctx.Exec.WriteFile(filepath.Join(ctx.Config.ProjectDir(), f.Path), []byte(f.Content), mode)
+}
@@ -0,0 +1,310 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/WillyV3/nextjs-templater/internal/executor"
+)
+
+func TestCreateNextAppKeepsExistingDirectory(t *testing.T) {
+	parent := t.TempDir()
+	precious := filepath.Join(parent, "myapp", "precious.txt")
+	if err := os.MkdirAll(filepath.Dir(precious), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(precious, []byte("keep me"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := Config{AppName: "myapp", ParentDir: parent}
+	engine := &Engine{Steps: []Step{createNextAppStep()}}
+	_, err := engine.Run(NewContext(cfg, io.Discard, executor.Real{}))
+	if err == nil {
+		t.Fatal("Run succeeded over an existing directory")
+	}
+	if _, err := os.Stat(precious); err != nil {
+		t.Fatalf("existing project file is gone: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(parent, "myapp", StateFile)); !os.IsNotExist(err) {
+		t.Errorf("%s was written into a directory the run did not create", StateFile)
+	}
+}
+
+func TestCreateNextAppRollsBackWhatItCreated(t *testing.T) {
+	rec := &executor.Recording{Fail: func(c Command) error { return errors.New("npm exploded") }}
+	cfg := Config{AppName: "myapp", ParentDir: "/work"}
+	engine := &Engine{Steps: []Step{createNextAppStep()}}
+	if _, err := engine.Run(NewContext(cfg, io.Discard, rec)); err == nil {
+		t.Fatal("Run succeeded although create-next-app failed")
+	}
+	removed := rec.Removed()
+	if len(removed) != 1 || removed[0] != cfg.ProjectDir() {
+		t.Errorf("removed %v, want only %s", removed, cfg.ProjectDir())
+	}
+}
+
+// fakeStep runs one command named after the step
+func fakeStep(name string) Step {
+	return Step{
+		Name:  name,
+		Title: name,
+		Commands: func(ctx *Context) []Command {
+			return []Command{{Name: name}}
+		},
+	}
+}
+
+// commandNames lists the programs a recording ran, in order
+func commandNames(rec *executor.Recording) []string {
+	var names []string
+	for _, c := range rec.Commands() {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func statuses(results []StepResult) map[string]Status {
+	m := map[string]Status{}
+	for _, r := range results {
+		m[r.Name] = r.Status
+	}
+	return m
+}
+
+func testConfig() Config {
+	return Config{AppName: "myapp", ParentDir: "/work"}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRunOrderAndWhen(t *testing.T) {
+	skipped := fakeStep("skipped")
+	skipped.When = func(cfg Config) bool { return cfg.Docker }
+	engine := &Engine{Steps: []Step{fakeStep("first"), skipped, fakeStep("second"), fakeStep("third")}}
+
+	rec := &executor.Recording{}
+	results, err := engine.Run(NewContext(testConfig(), io.Discard, rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := commandNames(rec), []string{"first", "second", "third"}; !equal(got, want) {
+		t.Errorf("ran %v, want %v", got, want)
+	}
+	if len(results) != 3 {
+		t.Errorf("got %d results, want the 3 planned steps", len(results))
+	}
+
+	cfg := testConfig()
+	cfg.Docker = true
+	if plan := engine.Plan(cfg); len(plan) != 4 || plan[1].Name != "skipped" {
+		t.Errorf("plan with Docker = %d steps, want skipped in second place", len(plan))
+	}
+}
+
+func TestFallbackAndOptional(t *testing.T) {
+	step := Step{
+		Name: "install",
+		Commands: func(ctx *Context) []Command {
+			return []Command{
+				{Name: "primary", Fallback: []Command{{Name: "fallback-1"}, {Name: "fallback-2"}}},
+				{Name: "extra", Optional: true},
+				{Name: "last"},
+			}
+		},
+	}
+	rec := &executor.Recording{Fail: func(c Command) error {
+		if c.Name == "primary" || c.Name == "extra" {
+			return errors.New("exit status 1")
+		}
+		return nil
+	}}
+	results, err := (&Engine{Steps: []Step{step}}).Run(NewContext(testConfig(), io.Discard, rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := commandNames(rec), []string{"primary", "fallback-1", "fallback-2", "extra", "last"}; !equal(got, want) {
+		t.Errorf("ran %v, want %v", got, want)
+	}
+	// One warning for the fallback and one for the optional command
+	if results[0].Status != StatusDone || len(results[0].Warnings) != 2 {
+		t.Errorf("status %v with warnings %v, want done with two warnings", results[0].Status, results[0].Warnings)
+	}
+
+	// A failing fallback fails the step
+	rec = &executor.Recording{Fail: func(c Command) error {
+		if c.Name == "primary" || c.Name == "fallback-1" {
+			return errors.New("exit status 1")
+		}
+		return nil
+	}}
+	if _, err := (&Engine{Steps: []Step{step}}).Run(NewContext(testConfig(), io.Discard, rec)); err == nil {
+		t.Error("Run succeeded although the fallback failed")
+	}
+	if got, want := commandNames(rec), []string{"primary", "fallback-1"}; !equal(got, want) {
+		t.Errorf("ran %v, want %v", got, want)
+	}
+}
+
+func TestRollbackOnlyAfterRealFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		pre      error
+		fail     bool
+		rollback bool
+	}{
+		{"success", nil, false, false},
+		{"precondition failed", errors.New("not ready"), false, false},
+		{"command failed", nil, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rolledBack := false
+			step := fakeStep("step")
+			step.Pre = func(*Context) error { return tt.pre }
+			step.Rollback = func(*Context) error {
+				rolledBack = true
+				return nil
+			}
+			rec := &executor.Recording{Fail: func(Command) error {
+				if tt.fail {
+					return errors.New("exit status 1")
+				}
+				return nil
+			}}
+			(&Engine{Steps: []Step{step}}).Run(NewContext(testConfig(), io.Discard, rec))
+			if rolledBack != tt.rollback {
+				t.Errorf("rolled back = %t, want %t", rolledBack, tt.rollback)
+			}
+		})
+	}
+}
+
+func TestResumeCompletedAndUntil(t *testing.T) {
+	repeat := fakeStep("repeat")
+	repeat.Repeat = true
+	engine := &Engine{
+		Steps:     []Step{fakeStep("one"), repeat, fakeStep("two"), fakeStep("three"), fakeStep("four")},
+		Completed: []string{"one", "repeat"},
+		Until:     "three",
+	}
+
+	rec := &executor.Recording{}
+	results, err := engine.Run(NewContext(testConfig(), io.Discard, rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := commandNames(rec), []string{"repeat", "two", "three"}; !equal(got, want) {
+		t.Errorf("ran %v, want %v", got, want)
+	}
+	want := map[string]Status{
+		"one":    StatusSkipped,
+		"repeat": StatusDone,
+		"two":    StatusDone,
+		"three":  StatusDone,
+		"four":   StatusPending,
+	}
+	got := statuses(results)
+	for name, status := range want {
+		if got[name] != status {
+			t.Errorf("%s is %v, want %v", name, got[name], status)
+		}
+	}
+}
+
+func TestFailureRecordedForResume(t *testing.T) {
+	create := Step{
+		Name: "create",
+		Commands: func(ctx *Context) []Command {
+			return []Command{{Name: "create", Creates: ctx.Config.ProjectDir()}}
+		},
+	}
+	rec := &executor.Recording{Fail: func(c Command) error {
+		if c.Name == "broken" {
+			return errors.New("exit status 1")
+		}
+		return nil
+	}}
+	cfg := testConfig()
+	engine := &Engine{Steps: []Step{create, fakeStep("broken"), fakeStep("after")}}
+	if _, err := engine.Run(NewContext(cfg, io.Discard, rec)); err == nil {
+		t.Fatal("Run succeeded although a step failed")
+	}
+
+	data, ok := rec.File(filepath.Join(cfg.ProjectDir(), StateFile))
+	if !ok {
+		t.Fatalf("%s was not written", StateFile)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatal(err)
+	}
+	if !equal(state.Completed, []string{"create"}) || state.Failed != "broken" {
+		t.Errorf("state completed %v failed %q, want [create] and broken", state.Completed, state.Failed)
+	}
+}
+
+func TestDryRunSummary(t *testing.T) {
+	step := Step{
+		Name: "scaffold",
+		Commands: func(ctx *Context) []Command {
+			return []Command{
+				{Name: "npx", Args: []string{"create-next-app"}, Creates: ctx.Config.ProjectDir()},
+				{Name: "npm", Args: []string{"install"}},
+			}
+		},
+		Files: func(ctx *Context) ([]File, error) {
+			return []File{{Path: "README.md", Content: "# myapp\n"}}, nil
+		},
+	}
+	cfg := Config{AppName: "myapp", ParentDir: t.TempDir()}
+	dry := executor.NewDryRun()
+	if _, err := (&Engine{Steps: []Step{step}}).Run(NewContext(cfg, io.Discard, dry)); err != nil {
+		t.Fatal(err)
+	}
+	// README.md and the state file
+	if got, want := dry.Summary(), "2 commands would run, 2 files would be written"; got != want {
+		t.Errorf("summary %q, want %q", got, want)
+	}
+	if _, err := os.Stat(cfg.ProjectDir()); !os.IsNotExist(err) {
+		t.Errorf("dry run created %s", cfg.ProjectDir())
+	}
+}
+
+func TestPreSkipsStep(t *testing.T) {
+	engine := &Engine{Steps: []Step{claudeStep(), fakeStep("after")}}
+
+	rec := &executor.Recording{Missing: []string{"claudenew"}}
+	results, err := engine.Run(NewContext(testConfig(), io.Discard, rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := commandNames(rec), []string{"after"}; !equal(got, want) {
+		t.Errorf("ran %v, want %v", got, want)
+	}
+	if results[0].Status != StatusSkipped {
+		t.Errorf("claude is %v, want skipped", results[0].Status)
+	}
+
+	rec = &executor.Recording{}
+	if _, err := engine.Run(NewContext(testConfig(), io.Discard, rec)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := commandNames(rec), []string{"claudenew", "after"}; !equal(got, want) {
+		t.Errorf("ran %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package scaffold
+
+import (
+	"embed"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:embed files/*.tmpl
+var fileTemplates embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"matchers": matchers,
+}).ParseFS(fileTemplates, "files/*.tmpl"))
+
+// packageManager holds the commands generated files use to install and run scripts
+type packageManager struct {
+	Name     string
+	Install  string // clean install from the lockfile
+	Run      string
+	Exec     string
+	Lockfile string
+}
+
+// npm is the package manager create-next-app is run with
+var npm = packageManager{
+	Name:     "npm",
+	Install:  "npm ci",
+	Run:      "npm run",
+	Exec:     "npx",
+	Lockfile: "package-lock.json",
+}
+
+// fileData is what every file template gets rendered with
+type fileData struct {
+	Config
+	ProjectName string
+	Secret      string
+	NodeVersion string
+	PM          packageManager
+}
+
+func newFileData(ctx *Context) fileData {
+	return fileData{
+		Config:      ctx.Config,
+		ProjectName: ctx.Config.ProjectName(),
+		Secret:      ctx.Var("auth_secret"),
+		NodeVersion: NodeVersion,
+		PM:          npm,
+	}
+}
+
+// render executes the named template from files/
+func render(name string, data any) (string, error) {
+	var b strings.Builder
+	if err := templates.ExecuteTemplate(&b, name, data); err != nil {
+		return "", fmt.Errorf("render %s: %w", name, err)
+	}
+	return b.String(), nil
+}
+
+// renderFiles renders a target path -> template name mapping in order
+func renderFiles(data any, pairs ...string) ([]File, error) {
+	var files []File
+	for i := 0; i+1 < len(pairs); i += 2 {
+		content, err := render(pairs[i+1], data)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: pairs[i], Content: content})
+	}
+	return files, nil
+}
+
+// matchers quotes routes for createRouteMatcher
+func matchers(routes []string) string {
+	quoted := make([]string, len(routes))
+	for i, r := range routes {
+		quoted[i] = strconv.Quote(r)
+	}
+	return strings.Join(quoted, ", ")
+}
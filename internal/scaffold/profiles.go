@@ -0,0 +1,114 @@
+package scaffold
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+//go:embed profiles.json
+var builtinProfiles []byte
+
+// Profile pins the version of every package generation installs or runs
+// through npx, so a nextui release keeps producing the same project
+type Profile struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Tag is the dist-tag used for packages not listed, "latest" when empty
+	Tag      string            `json:"tag,omitempty"`
+	Packages map[string]string `json:"packages,omitempty"`
+}
+
+// Pin returns the npm spec for pkg, like "shadcn@3.3.1"
+func (p Profile) Pin(pkg string) string {
+	if v := p.Packages[pkg]; v != "" {
+		return pkg + "@" + v
+	}
+	if p.Tag != "" {
+		return pkg + "@" + p.Tag
+	}
+	return pkg + "@latest"
+}
+
+// profileFile is the layout of profiles.json
+type profileFile struct {
+	Default  string             `json:"default"`
+	Profiles map[string]Profile `json:"profiles"`
+}
+
+// UserProfilesPath is where extra profiles and the default can be configured
+func UserProfilesPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "nextui", "profiles.json")
+}
+
+// loadProfiles merges the built-in profiles with the user's profiles file;
+// user profiles replace built-in ones of the same name
+func loadProfiles() (profileFile, error) {
+	var pf profileFile
+	if err := json.Unmarshal(builtinProfiles, &pf); err != nil {
+		return pf, fmt.Errorf("built-in profiles: %w", err)
+	}
+
+	if path := UserProfilesPath(); path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			var user profileFile
+			if err := json.Unmarshal(data, &user); err != nil {
+				return pf, fmt.Errorf("read %s: %w", path, err)
+			}
+			for name, p := range user.Profiles {
+				pf.Profiles[name] = p
+			}
+			if user.Default != "" {
+				pf.Default = user.Default
+			}
+		case !os.IsNotExist(err):
+			return pf, err
+		}
+	}
+
+	for name, p := range pf.Profiles {
+		p.Name = name
+		pf.Profiles[name] = p
+	}
+	return pf, nil
+}
+
+// ProfileNames lists the available profiles, sorted
+func ProfileNames() []string {
+	pf, err := loadProfiles()
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(pf.Profiles))
+	for name := range pf.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadProfile returns the named profile, or the default one when name is empty
+func LoadProfile(name string) (Profile, error) {
+	pf, err := loadProfiles()
+	if err != nil {
+		return Profile{}, err
+	}
+	if name == "" {
+		name = pf.Default
+	}
+	p, ok := pf.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown version profile %q (available: %s)", name, strings.Join(ProfileNames(), ", "))
+	}
+	return p, nil
+}
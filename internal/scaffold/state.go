@@ -0,0 +1,71 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// StateFile records generation progress inside the project directory
+const StateFile = ".nextui.json"
+
+// State is what gets written to StateFile after every step
+type State struct {
+	Config    Config    `json:"config"`
+	Completed []string  `json:"completed"`
+	Failed    string    `json:"failed,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// LoadState reads the state of an earlier run from a project directory
+func LoadState(projectDir string) (*State, error) {
+	data, err := os.ReadFile(filepath.Join(projectDir, StateFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s has no %s, it was not generated by nextui or failed before the project was created", projectDir, StateFile)
+		}
+		return nil, err
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("read %s: %w", StateFile, err)
+	}
+
+	// The project may have been moved since, so trust its current location
+	abs, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	state.Config.ParentDir = filepath.Dir(abs)
+	state.Config.AppName = filepath.Base(abs)
+	return &state, nil
+}
+
+// IsCompleted reports whether the named step already finished
+func (s *State) IsCompleted(name string) bool {
+	for _, done := range s.Completed {
+		if done == name {
+			return true
+		}
+	}
+	return false
+}
+
+// save writes the state once the project directory exists
+func (s *State) save(ctx *Context) error {
+	dir := ctx.Config.ProjectDir()
+	if !ctx.Exec.Exists(dir) {
+		return nil
+	}
+	s.Config = ctx.Config
+	s.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ctx.Exec.WriteFile(filepath.Join(dir, StateFile), append(data, '\n'), 0644)
+}
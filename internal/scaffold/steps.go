@@ -0,0 +1,825 @@
+package scaffold
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/WillyV3/nextjs-templater/internal/executor"
+	"github.com/WillyV3/nextjs-templater/internal/verify"
+)
+
+// Steps returns the generation phases in run order. They mirror the phases of
+// script/create-nextjs-shadcn.sh, which is kept as an escape hatch.
+func Steps() []Step {
+	return []Step{
+		nodeStep(),
+		createNextAppStep(),
+		shadcnInitStep(),
+		shadcnComponentsStep(),
+		clerkQuickstartStep(),
+		clerkScaffoldStep(),
+		betterAuthStep(),
+		packagesStep(),
+		testingStep(),
+		toolingStep(),
+		claudeStep(),
+		dockerStep(),
+		ciStep(),
+		formatStep(),
+		verifyStep(),
+		gitStep(),
+		gitRemoteStep(),
+	}
+}
+
+// Summary returns the closing lines printed after a successful run with the
+// given step results
+func Summary(cfg Config, results []StepResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "✅ Done. Project at: %s\n", cfg.ProjectDir())
+	if cfg.Theme != "" {
+		if warned(results, "shadcn-init") {
+			// The theme add fell back to a plain init
+			fmt.Fprintf(&b, "   ⚠️  Theme %s could not be applied, see the warnings above\n", cfg.Theme)
+		} else {
+			fmt.Fprintf(&b, "   Theme applied: %s\n", cfg.Theme)
+		}
+	}
+	if cfg.Versions.Name != "" {
+		fmt.Fprintf(&b, "   Versions: %s profile (%s)\n", cfg.Versions.Name, cfg.Pkg("create-next-app"))
+	}
+	if cfg.Next != (NextOptions{}) {
+		fmt.Fprintf(&b, "   Layout: %s\n", cfg.Layout())
+	}
+	switch {
+	case cfg.Auth == AuthClerk && cfg.Clerk.Scaffold:
+		b.WriteString("   Clerk authentication: Installed with middleware and protected /dashboard\n")
+		fmt.Fprintf(&b, "   Public routes: %s\n", strings.Join(cfg.Clerk.PublicRoutes, ", "))
+		if len(cfg.Clerk.ProtectedRoutes) == 0 {
+			b.WriteString("   Protected routes: all non-public routes\n")
+		} else {
+			fmt.Fprintf(&b, "   Protected routes: %s\n", strings.Join(cfg.Clerk.ProtectedRoutes, ", "))
+		}
+		if cfg.Clerk.Organizations {
+			b.WriteString("   Organizations: Enabled (turn them on in the Clerk dashboard)\n")
+		}
+		b.WriteString("   Set CLERK_SECRET_KEY and NEXT_PUBLIC_CLERK_PUBLISHABLE_KEY in .env.local when leaving keyless mode\n")
+	case cfg.Auth == AuthClerk:
+		b.WriteString("   Clerk authentication: Installed\n")
+		b.WriteString("   Don't forget to set your CLERK_SECRET_KEY and NEXT_PUBLIC_CLERK_PUBLISHABLE_KEY\n")
+	case cfg.Auth == AuthBetterAuth:
+		b.WriteString("   Better Auth: Installed with Kysely + SQLite\n")
+		b.WriteString("   Database: SQLite (./auth.db created on first run)\n")
+		fmt.Fprintf(&b, "   Config: %s and %s created\n", cfg.SrcPath("lib/auth."+cfg.ScriptExt()), cfg.SrcPath("lib/auth-client."+cfg.ScriptExt()))
+		b.WriteString("   Environment: .env.local created with secrets\n")
+		b.WriteString("   Add your GitHub OAuth credentials to .env.local for social login\n")
+	}
+	if cfg.Docker {
+		b.WriteString("   Docker: Dockerfile, .dockerignore and docker-compose.yml (standalone output)\n")
+	}
+	switch cfg.Tooling {
+	case ToolingBiome:
+		b.WriteString("   Tooling: Biome (npm run lint, npm run format)\n")
+	case ToolingESLintPrettier:
+		b.WriteString("   Tooling: ESLint + Prettier with Tailwind class sorting (npm run lint, npm run format)\n")
+	}
+	if cfg.GitHooks {
+		b.WriteString("   Git hooks: husky runs lint-staged before every commit\n")
+	}
+	if cfg.Testing.Vitest {
+		b.WriteString("   Tests: Vitest + Testing Library (npm test)\n")
+	}
+	if cfg.Testing.Playwright {
+		b.WriteString("   Tests: Playwright end-to-end (npm run test:e2e)\n")
+	}
+	if cfg.CI.GitHub {
+		b.WriteString("   CI: GitHub Actions workflow in .github/workflows/ci.yml\n")
+	}
+	if cfg.CI.GitLab {
+		b.WriteString("   CI: GitLab pipeline in .gitlab-ci.yml\n")
+	}
+	if cfg.Verify {
+		if cfg.TypeScript() {
+			b.WriteString("   Verified: typecheck, lint and production build passed\n")
+		} else {
+			b.WriteString("   Verified: lint and production build passed\n")
+		}
+	}
+	if !cfg.Git.Skip {
+		fmt.Fprintf(&b, "   Git: committed %q", cfg.Git.CommitMessage())
+		if cfg.Git.Remote != "" && cfg.Git.Push {
+			fmt.Fprintf(&b, " and pushed to %s", cfg.Git.Remote)
+		} else if cfg.Git.Remote != "" {
+			fmt.Fprintf(&b, ", origin set to %s", cfg.Git.Remote)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "Run: cd %s && npm run dev\n", cfg.ProjectDir())
+	return b.String()
+}
+
+func nodeStep() Step {
+	return Step{
+		Name:   "node",
+		Title:  "Check Node.js setup",
+		Repeat: true, // sets PATH for every later step
+		Pre: func(ctx *Context) error {
+			var missing []string
+			for _, bin := range []string{"node", "npm"} {
+				if _, err := ctx.Exec.LookPath(bin); err != nil {
+					missing = append(missing, bin)
+				}
+			}
+			if len(missing) > 0 {
+				ctx.Logf("Please install Node.js and npm before continuing")
+				ctx.Logf("  - Ubuntu/Debian: sudo apt install nodejs npm")
+				ctx.Logf("  - macOS: brew install node")
+				ctx.Logf("  - Or download from: https://nodejs.org/")
+				return fmt.Errorf("missing required dependencies: %s", strings.Join(missing, ", "))
+			}
+
+			// Prefer Node.js 20 from NVM when it is installed, like `nvm use 20`
+			if bin := nvmNodeBin(NodeVersion); bin != "" {
+				ctx.Logf("📦 Found NVM, using Node.js from %s", bin)
+				ctx.SetEnv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+			} else {
+				ctx.Logf("📦 Using system Node.js")
+			}
+			return nil
+		},
+		Commands: func(ctx *Context) []Command {
+			return []Command{
+				{Dir: ctx.Config.ParentDir, Name: "node", Args: []string{"--version"}},
+				{Dir: ctx.Config.ParentDir, Name: "npm", Args: []string{"--version"}},
+			}
+		},
+	}
+}
+
+// nvmNodeBin returns the bin directory of the newest NVM install of a major version
+func nvmNodeBin(major string) string {
+	nvmDir := os.Getenv("NVM_DIR")
+	if nvmDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		nvmDir = filepath.Join(home, ".nvm")
+	}
+	matches, _ := filepath.Glob(filepath.Join(nvmDir, "versions", "node", "v"+major+".*"))
+	if len(matches) == 0 {
+		return ""
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return versionLess(filepath.Base(matches[i]), filepath.Base(matches[j]))
+	})
+	return filepath.Join(matches[len(matches)-1], "bin")
+}
+
+// versionLess compares "v20.11.1" style versions numerically
+func versionLess(a, b string) bool {
+	pa := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	pb := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, _ := strconv.Atoi(pa[i])
+		nb, _ := strconv.Atoi(pb[i])
+		if na != nb {
+			return na < nb
+		}
+	}
+	return len(pa) < len(pb)
+}
+
+func createNextAppStep() Step {
+	return Step{
+		Name:  "create-next-app",
+		Title: "Create Next.js app",
+		Pre: func(ctx *Context) error {
+			if ctx.Exec.Exists(ctx.Config.ProjectDir()) {
+				return fmt.Errorf("%s already exists", ctx.Config.ProjectDir())
+			}
+			// Only a directory this run creates may be rolled back
+			ctx.vars[createdDirVar] = ctx.Config.ProjectDir()
+			return nil
+		},
+		Commands: func(ctx *Context) []Command {
+			return []Command{{
+				Dir:     ctx.Config.ParentDir,
+				Name:    "npx",
+				Args:    append([]string{ctx.Config.Pkg("create-next-app"), ctx.Config.ProjectName()}, createNextAppFlags(ctx.Config)...),
+				Input:   "n\n",
+				Creates: ctx.Config.ProjectDir(),
+			}}
+		},
+		Files: func(ctx *Context) ([]File, error) {
+			// Written up front so nothing secret is ever staged
+			return renderFiles(newFileData(ctx), ".gitignore", "gitignore.tmpl")
+		},
+		Rollback: func(ctx *Context) error {
+			if ctx.Var(createdDirVar) != ctx.Config.ProjectDir() {
+				return nil
+			}
+			return ctx.Exec.RemoveAll(ctx.Config.ProjectDir())
+		},
+	}
+}
+
+// createdDirVar holds the project directory once create-next-app checked it
+// did not exist yet
+const createdDirVar = "created_project_dir"
+
+// createNextAppFlags turns the config into create-next-app options, leaving
+// no prompt unanswered except the ones fed through stdin
+func createNextAppFlags(cfg Config) []string {
+	flag := func(on bool, yes, no string) string {
+		if on {
+			return yes
+		}
+		return no
+	}
+	flags := []string{
+		flag(cfg.TypeScript(), "--typescript", "--javascript"),
+		"--tailwind",
+		flag(cfg.Tooling == ToolingBiome, "--biome", "--eslint"),
+		flag(cfg.AppRouter(), "--app", "--no-app"),
+		flag(cfg.Next.NoSrcDir, "--no-src-dir", "--src-dir"),
+		flag(cfg.Next.NoTurbopack, "--no-turbopack", "--turbopack"),
+		"--import-alias", cfg.ImportAlias(),
+	}
+	if cfg.Next.ReactCompiler {
+		flags = append(flags, "--react-compiler")
+	}
+	return append(flags, "--disable-git")
+}
+
+func shadcnInitStep() Step {
+	return Step{
+		Name:  "shadcn-init",
+		Title: "Initialize shadcn",
+		Pre: func(ctx *Context) error {
+			if !ctx.Exec.Exists(ctx.Config.ProjectDir()) {
+				return fmt.Errorf("project directory was not created: %s", ctx.Config.ProjectDir())
+			}
+			return nil
+		},
+		Commands: func(ctx *Context) []Command {
+			init := Command{Name: "npx", Args: []string{ctx.Config.Pkg("shadcn"), "init"}, Input: "1\n1\n"}
+			if ctx.Config.Theme == "" {
+				return []Command{init}
+			}
+
+			// The first add inits shadcn, the second applies the theme
+			themeURL := fmt.Sprintf("https://tweakcn.com/r/themes/%s.json", ctx.Config.Theme)
+			return []Command{
+				{Name: "npx", Args: []string{ctx.Config.Pkg("shadcn"), "add", themeURL}, Yes: true, Fallback: []Command{init}},
+				{Name: "npx", Args: []string{ctx.Config.Pkg("shadcn"), "add", themeURL}, Yes: true, Optional: true},
+			}
+		},
+		Files: func(ctx *Context) ([]File, error) {
+			if f, ok := shadcnAliases(ctx); ok {
+				return []File{f}, nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+// shadcnAliases points the components.json aliases at a custom import alias,
+// so components added later import from the right place
+func shadcnAliases(ctx *Context) (File, bool) {
+	alias := ctx.Config.Alias()
+	if alias == "@/" {
+		return File{}, false
+	}
+	data, err := ctx.Exec.ReadFile(filepath.Join(ctx.Config.ProjectDir(), "components.json"))
+	if err != nil {
+		if dryRun(ctx) {
+			ctx.Logf("Would point the components.json aliases at %s", alias)
+		}
+		return File{}, false
+	}
+	content := strings.ReplaceAll(string(data), `"@/`, `"`+alias)
+	return File{Path: "components.json", Content: content}, content != string(data)
+}
+
+// dryRun reports whether the run only previews, so files earlier steps
+// would have created can't be read
+func dryRun(ctx *Context) bool {
+	_, ok := ctx.Exec.(*executor.DryRun)
+	return ok
+}
+
+func shadcnComponentsStep() Step {
+	return Step{
+		Name:  "shadcn-components",
+		Title: "Install all shadcn components",
+		Commands: func(ctx *Context) []Command {
+			return []Command{
+				{Name: "npx", Args: []string{ctx.Config.Pkg("shadcn"), "add", "--all"}, Yes: true, Optional: true},
+			}
+		},
+	}
+}
+
+func clerkQuickstartStep() Step {
+	return Step{
+		Name:  "clerk",
+		Title: "Install Clerk quickstart",
+		When: func(cfg Config) bool {
+			return cfg.Auth == AuthClerk && !cfg.Clerk.Scaffold
+		},
+		Commands: func(ctx *Context) []Command {
+			return []Command{
+				{Name: "npx", Args: []string{ctx.Config.Pkg("shadcn"), "add", "@clerk/nextjs-quickstart"}, Yes: true},
+			}
+		},
+	}
+}
+
+func clerkScaffoldStep() Step {
+	return Step{
+		Name:  "clerk",
+		Title: "Install Clerk with protected routes",
+		When: func(cfg Config) bool {
+			return cfg.Auth == AuthClerk && cfg.Clerk.Scaffold
+		},
+		Commands: func(ctx *Context) []Command {
+			return []Command{
+				{Name: "npm", Args: append([]string{"install"}, ctx.Config.Pkgs("@clerk/nextjs")...)},
+			}
+		},
+		Files: func(ctx *Context) ([]File, error) {
+			cfg := ctx.Config
+			js, jsx := cfg.ScriptExt(), cfg.ComponentExt()
+			pairs := []string{
+				cfg.SrcPath("middleware." + js), "clerk-middleware.ts.tmpl",
+				cfg.SrcPath("components/site-header." + jsx), "clerk-site-header.tsx.tmpl",
+				cfg.SrcPath("app/layout." + jsx), "clerk-layout.tsx.tmpl",
+				cfg.SrcPath("app/sign-in/[[...sign-in]]/page." + jsx), "clerk-sign-in.tsx.tmpl",
+				cfg.SrcPath("app/sign-up/[[...sign-up]]/page." + jsx), "clerk-sign-up.tsx.tmpl",
+				cfg.SrcPath("app/dashboard/page." + jsx), "clerk-dashboard.tsx.tmpl",
+				".env.local", "clerk-env.tmpl",
+			}
+			if cfg.Clerk.Organizations {
+				pairs = append(pairs, cfg.SrcPath("app/org-selection/page."+jsx), "clerk-org-selection.tsx.tmpl")
+			}
+			return renderFiles(newFileData(ctx), pairs...)
+		},
+	}
+}
+
+func betterAuthStep() Step {
+	return Step{
+		Name:  "better-auth",
+		Title: "Install Better Auth with SQLite",
+		When: func(cfg Config) bool {
+			return cfg.Auth == AuthBetterAuth
+		},
+		Commands: func(ctx *Context) []Command {
+			cmds := []Command{
+				{Name: "npm", Args: append([]string{"install"}, ctx.Config.Pkgs("better-auth", "better-sqlite3")...)},
+			}
+			if ctx.Config.TypeScript() {
+				cmds = append(cmds, Command{Name: "npm", Args: append([]string{"install", "--save-dev"}, ctx.Config.Pkgs("@types/better-sqlite3")...)})
+			}
+			return append(cmds, Command{Name: "npx", Args: []string{ctx.Config.Pkg("@better-auth/cli"), "secret"}, Capture: "auth_secret"})
+		},
+		Files: func(ctx *Context) ([]File, error) {
+			cfg := ctx.Config
+			js := cfg.ScriptExt()
+			// lib/ lives next to app/ so the "@/lib/auth" import resolves
+			route, routeTmpl := cfg.SrcPath("app/api/auth/[...all]/route."+js), "better-auth-route.ts.tmpl"
+			if cfg.Next.PagesRouter {
+				route, routeTmpl = cfg.SrcPath("pages/api/auth/[...all]."+js), "better-auth-pages-route.ts.tmpl"
+			}
+			return renderFiles(newFileData(ctx),
+				".env.local", "better-auth-env.tmpl",
+				cfg.SrcPath("lib/auth."+js), "better-auth.ts.tmpl",
+				cfg.SrcPath("lib/auth-client."+js), "better-auth-client.ts.tmpl",
+				route, routeTmpl,
+			)
+		},
+	}
+}
+
+func packagesStep() Step {
+	return Step{
+		Name:  "packages",
+		Title: "Add additional packages",
+		Commands: func(ctx *Context) []Command {
+			cmds := []Command{
+				{Name: "npm", Args: append([]string{"install"}, ctx.Config.Pkgs("lucide-react", "next-themes")...)},
+			}
+			if cmd, ok := recordProfile(ctx.Config.Versions); ok {
+				cmds = append(cmds, cmd)
+			}
+			return cmds
+		},
+	}
+}
+
+// warned reports whether the named step finished with warnings
+func warned(results []StepResult, name string) bool {
+	for _, r := range results {
+		if r.Name == name && len(r.Warnings) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// recordProfile stores the version profile under "nextui" in package.json.
+// .nextui.json is git-ignored, so this is the copy that gets committed.
+func recordProfile(p Profile) (Command, bool) {
+	if p.Name == "" {
+		return Command{}, false
+	}
+	data, err := json.Marshal(struct {
+		Profile  string            `json:"profile"`
+		Tag      string            `json:"tag,omitempty"`
+		Packages map[string]string `json:"packages,omitempty"`
+	}{p.Name, p.Tag, p.Packages})
+	if err != nil {
+		return Command{}, false
+	}
+	return Command{Name: "npm", Args: []string{"pkg", "set", "--json", "nextui=" + string(data)}}, true
+}
+
+func testingStep() Step {
+	return Step{
+		Name:  "testing",
+		Title: "Set up testing",
+		When: func(cfg Config) bool {
+			return cfg.Testing.Vitest || cfg.Testing.Playwright
+		},
+		Commands: func(ctx *Context) []Command {
+			var cmds []Command
+			if ctx.Config.Testing.Vitest {
+				cmds = append(cmds,
+					Command{Name: "npm", Args: append([]string{"install", "--save-dev"}, ctx.Config.Pkgs(
+						"vitest", "@vitejs/plugin-react", "vite-tsconfig-paths", "jsdom",
+						"@testing-library/react", "@testing-library/dom", "@testing-library/jest-dom", "@testing-library/user-event")...)},
+					Command{Name: "npm", Args: []string{"pkg", "set", "scripts.test=vitest run", "scripts.test:watch=vitest"}},
+				)
+			}
+			if ctx.Config.Testing.Playwright {
+				cmds = append(cmds,
+					Command{Name: "npm", Args: append([]string{"install", "--save-dev"}, ctx.Config.Pkgs("@playwright/test")...)},
+					Command{Name: "npm", Args: []string{"pkg", "set", "scripts.test:e2e=playwright test"}},
+					// Browsers are a big download; the tests can install them later
+					Command{Name: "npx", Args: []string{"playwright", "install", "chromium"}, Optional: true},
+				)
+			}
+			return cmds
+		},
+		Files: func(ctx *Context) ([]File, error) {
+			cfg := ctx.Config
+			var pairs []string
+			if cfg.Testing.Vitest {
+				pairs = append(pairs,
+					"vitest.config.m"+cfg.ScriptExt(), "vitest.config.mts.tmpl",
+					"vitest.setup."+cfg.ScriptExt(), "vitest.setup.ts.tmpl",
+					cfg.SrcPath("__tests__/button.test."+cfg.ComponentExt()), "button.test.tsx.tmpl",
+				)
+			}
+			if cfg.Testing.Playwright {
+				pairs = append(pairs,
+					"playwright.config."+cfg.ScriptExt(), "playwright.config.ts.tmpl",
+					"e2e/home.spec."+cfg.ScriptExt(), "home.spec.ts.tmpl",
+				)
+			}
+			return renderFiles(newFileData(ctx), pairs...)
+		},
+	}
+}
+
+func toolingStep() Step {
+	return Step{
+		Name:  "tooling",
+		Title: "Set up linting and formatting",
+		When: func(cfg Config) bool {
+			return cfg.Tooling != ""
+		},
+		Commands: func(ctx *Context) []Command {
+			var cmds []Command
+			switch ctx.Config.Tooling {
+			case ToolingBiome:
+				cmds = append(cmds,
+					Command{Name: "npm", Args: append([]string{"install", "--save-dev", "--save-exact"}, ctx.Config.Pkgs("@biomejs/biome")...)},
+					Command{Name: "npm", Args: []string{"pkg", "set", "scripts.lint=biome check", "scripts.format=biome format --write"}},
+				)
+			case ToolingESLintPrettier:
+				cmds = append(cmds,
+					Command{Name: "npm", Args: append([]string{"install", "--save-dev"}, ctx.Config.Pkgs("prettier", "prettier-plugin-tailwindcss", "eslint-config-prettier")...)},
+					Command{Name: "npm", Args: []string{"pkg", "set", "scripts.format=prettier --write .", "scripts.format:check=prettier --check ."}},
+				)
+			}
+			if ctx.Config.GitHooks {
+				cmds = append(cmds,
+					Command{Name: "npm", Args: append([]string{"install", "--save-dev"}, ctx.Config.Pkgs("husky", "lint-staged")...)},
+					Command{Name: "npm", Args: []string{"pkg", "set", "scripts.prepare=husky"}},
+				)
+				// husky installs into .git, so the repository has to exist first
+				if cmd, ok := gitInit(ctx); ok {
+					cmds = append(cmds, cmd)
+				}
+				cmds = append(cmds, Command{Name: "npx", Args: []string{"husky"}})
+			}
+			return cmds
+		},
+		Files: func(ctx *Context) ([]File, error) {
+			data := newFileData(ctx)
+			var files []File
+			var err error
+			switch ctx.Config.Tooling {
+			case ToolingBiome:
+				files, err = renderFiles(data, "biome.json", "biome.json.tmpl")
+			case ToolingESLintPrettier:
+				files, err = renderFiles(data,
+					".prettierrc", "prettierrc.tmpl",
+					".prettierignore", "prettierignore.tmpl",
+				)
+				if err == nil {
+					if f, ok := eslintPrettierConfig(ctx); ok {
+						files = append(files, f)
+					}
+				}
+			}
+			if err != nil || !ctx.Config.GitHooks {
+				return files, err
+			}
+
+			hooks, err := renderFiles(data,
+				".lintstagedrc.json", "lintstagedrc.json.tmpl",
+				".husky/pre-commit", "husky-pre-commit.tmpl",
+			)
+			if err != nil {
+				return nil, err
+			}
+			hooks[1].Mode = 0755
+			return append(files, hooks...), nil
+		},
+	}
+}
+
+// eslintPrettierConfig adds eslint-config-prettier to the ESLint config
+// create-next-app wrote, so ESLint stops fighting Prettier over formatting.
+// The FlatCompat (Next.js 15) and native flat config (Next.js 16) layouts
+// are handled for TypeScript and JavaScript projects; anything else is left
+// alone with a warning.
+func eslintPrettierConfig(ctx *Context) (File, bool) {
+	const name = "eslint.config.mjs" // create-next-app writes .mjs for TS and JS
+	data, err := ctx.Exec.ReadFile(filepath.Join(ctx.Config.ProjectDir(), name))
+	if err != nil {
+		if dryRun(ctx) {
+			// create-next-app didn't really run, so there is nothing to read
+			ctx.Logf("Would add eslint-config-prettier to %s", name)
+			return File{}, false
+		}
+		ctx.Logf("⚠️  %s not found, add eslint-config-prettier to it by hand", name)
+		return File{}, false
+	}
+
+	content := string(data)
+	switch {
+	case strings.Contains(content, "prettier"):
+		return File{}, false
+	case strings.Contains(content, `"next/typescript"`):
+		content = strings.Replace(content, `"next/typescript"`, `"next/typescript", "prettier"`, 1)
+	case strings.Contains(content, `"next/core-web-vitals"`):
+		// JavaScript projects only extend the web vitals config
+		content = strings.Replace(content, `"next/core-web-vitals"`, `"next/core-web-vitals", "prettier"`, 1)
+	case strings.Contains(content, "...nextTs,"):
+		content = flatPrettier(content, "...nextTs,")
+	case strings.Contains(content, "...nextVitals,"):
+		content = flatPrettier(content, "...nextVitals,")
+	default:
+		ctx.Logf("⚠️  Unrecognised %s, add eslint-config-prettier to it by hand", name)
+		return File{}, false
+	}
+	return File{Path: name, Content: content}, true
+}
+
+// flatPrettier imports eslint-config-prettier into a native flat config and
+// spreads it right after the last Next.js config, so it wins
+func flatPrettier(content, last string) string {
+	content = `import prettier from "eslint-config-prettier/flat";` + "\n" + content
+	return strings.Replace(content, last, last+"\n  prettier,", 1)
+}
+
+// formatStep runs the formatter over everything generated so the first
+// commit is already clean. Remaining lint errors only warn.
+func formatStep() Step {
+	return Step{
+		Name:  "format",
+		Title: "Format the codebase",
+		When: func(cfg Config) bool {
+			return cfg.Tooling != ""
+		},
+		Commands: func(ctx *Context) []Command {
+			if ctx.Config.Tooling == ToolingBiome {
+				return []Command{
+					{Name: "npx", Args: []string{"biome", "check", "--write", "."}, Optional: true},
+				}
+			}
+			return []Command{
+				{Name: "npx", Args: []string{"prettier", "--write", "."}, Optional: true},
+				{Name: "npx", Args: []string{"eslint", "--fix", "."}, Optional: true},
+			}
+		},
+	}
+}
+
+func claudeStep() Step {
+	return Step{
+		Name:  "claude",
+		Title: "Set up Claude directory",
+		Pre: func(ctx *Context) error {
+			if _, err := ctx.Exec.LookPath("claudenew"); err != nil {
+				return fmt.Errorf("claudenew is not installed: %w", ErrSkip)
+			}
+			return nil
+		},
+		Commands: func(ctx *Context) []Command {
+			return []Command{{Name: "claudenew", Optional: true}}
+		},
+	}
+}
+
+func dockerStep() Step {
+	return Step{
+		Name:  "docker",
+		Title: "Add Docker deployment files",
+		When: func(cfg Config) bool {
+			return cfg.Docker
+		},
+		Files: func(ctx *Context) ([]File, error) {
+			nextConfig, err := standaloneConfig(ctx)
+			if err != nil {
+				return nil, err
+			}
+			files, err := renderFiles(newFileData(ctx),
+				"Dockerfile", "dockerfile.tmpl",
+				".dockerignore", "dockerignore.tmpl",
+				"docker-compose.yml", "docker-compose.yml.tmpl",
+			)
+			return append(files, nextConfig), err
+		},
+	}
+}
+
+// nextConfigObject matches the opening of the config object create-next-app writes
+var nextConfigObject = regexp.MustCompile(`const nextConfig(\s*:\s*NextConfig)?\s*=\s*\{`)
+
+// standaloneConfig patches the Next.js config to emit the standalone server the
+// Dockerfile copies. A missing config (as in dry runs) starts from the default one.
+func standaloneConfig(ctx *Context) (File, error) {
+	name := ctx.Config.NextConfigFile()
+	data, err := ctx.Exec.ReadFile(filepath.Join(ctx.Config.ProjectDir(), name))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return File{}, err
+		}
+		content, err := render("next-config.ts.tmpl", newFileData(ctx))
+		if err != nil {
+			return File{}, err
+		}
+		data = []byte(content)
+	}
+
+	content := string(data)
+	if strings.Contains(content, "output:") {
+		return File{Path: name, Content: content}, nil
+	}
+	loc := nextConfigObject.FindStringIndex(content)
+	if loc == nil {
+		return File{}, fmt.Errorf("could not find the config object in %s; add output: \"standalone\" by hand", name)
+	}
+	content = content[:loc[1]] + "\n  output: \"standalone\"," + content[loc[1]:]
+	return File{Path: name, Content: content}, nil
+}
+
+func ciStep() Step {
+	return Step{
+		Name:  "ci",
+		Title: "Add CI workflow",
+		When: func(cfg Config) bool {
+			return cfg.CI.GitHub || cfg.CI.GitLab
+		},
+		Files: func(ctx *Context) ([]File, error) {
+			var pairs []string
+			if ctx.Config.CI.GitHub {
+				pairs = append(pairs, ".github/workflows/ci.yml", "github-ci.yml.tmpl")
+			}
+			if ctx.Config.CI.GitLab {
+				pairs = append(pairs, ".gitlab-ci.yml", "gitlab-ci.yml.tmpl")
+			}
+			return renderFiles(newFileData(ctx), pairs...)
+		},
+	}
+}
+
+func verifyStep() Step {
+	return Step{
+		Name:  "verify",
+		Title: "Verify typecheck, lint and build",
+		When: func(cfg Config) bool {
+			return cfg.Verify
+		},
+		Check: func(ctx *Context) error {
+			// Run every check so the report is complete, then fail on any error
+			ctx.Verification = nil
+			for _, c := range verify.Checks(ctx.Config.TypeScript()) {
+				var output bytes.Buffer
+				out := ctx.Out
+				ctx.Out = io.MultiWriter(out, &output)
+				start := time.Now()
+				err := runCommand(ctx, c.Command)
+				ctx.Out = out
+
+				r := verify.NewResult(c, output.String(), err, time.Since(start))
+				ctx.Verification = append(ctx.Verification, r)
+				if r.Passed {
+					ctx.Logf("✓ %s", c.Title)
+				} else {
+					ctx.Logf("✗ %s", c.Title)
+				}
+			}
+			if failed := verify.Failed(ctx.Verification); len(failed) > 0 {
+				return fmt.Errorf("verification failed: %s", strings.Join(failed, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+func gitStep() Step {
+	return Step{
+		Name:  "git",
+		Title: "Create initial commit",
+		When: func(cfg Config) bool {
+			return !cfg.Git.Skip
+		},
+		Pre: func(ctx *Context) error {
+			if _, err := ctx.Exec.LookPath("git"); err != nil {
+				return fmt.Errorf("git is not installed: %w", err)
+			}
+			_, err := ctx.Config.Git.AuthorEnv()
+			return err
+		},
+		Commands: func(ctx *Context) []Command {
+			var cmds []Command
+			// create-next-app runs with --disable-git, but the hooks setup or
+			// a script-made project may already have created a repository
+			if cmd, ok := gitInit(ctx); ok {
+				cmds = append(cmds, cmd)
+			}
+			author, _ := ctx.Config.Git.AuthorEnv()
+			return append(cmds,
+				Command{Name: "git", Args: []string{"add", "-A"}},
+				// The format step already cleaned everything, so skip the pre-commit hook
+				Command{Name: "git", Args: []string{"commit", "--quiet", "--no-verify", "-m", ctx.Config.Git.CommitMessage()}, Env: author},
+			)
+		},
+	}
+}
+
+// gitInit returns the command creating the project repository, if it has none
+func gitInit(ctx *Context) (Command, bool) {
+	gitDir := filepath.Join(ctx.Config.ProjectDir(), ".git")
+	if ctx.Exec.Exists(gitDir) {
+		return Command{}, false
+	}
+	return Command{Name: "git", Args: []string{"init"}, Creates: gitDir}, true
+}
+
+func gitRemoteStep() Step {
+	return Step{
+		Name:  "git-remote",
+		Title: "Set up git remote",
+		When: func(cfg Config) bool {
+			return !cfg.Git.Skip && cfg.Git.Remote != ""
+		},
+		Commands: func(ctx *Context) []Command {
+			remote := ctx.Config.Git.Remote
+			cmds := []Command{{
+				Name:     "git",
+				Args:     []string{"remote", "add", "origin", remote},
+				Fallback: []Command{{Name: "git", Args: []string{"remote", "set-url", "origin", remote}}},
+			}}
+			if ctx.Config.Git.Push {
+				cmds = append(cmds, Command{Name: "git", Args: []string{"push", "-u", "origin", "HEAD"}})
+			}
+			return cmds
+		},
+	}
+}
@@ -0,0 +1,221 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/WillyV3/nextjs-templater/internal/executor"
+)
+
+// git runs git in dir and returns its trimmed output
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestGitStepsCommitAndPush(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	// Keep the user's git config out of the way
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	tmp := t.TempDir()
+	remote := filepath.Join(tmp, "remote.git")
+	git(t, tmp, "init", "--quiet", "--bare", remote)
+
+	cfg := Config{AppName: "myapp", ParentDir: tmp}
+	cfg.Git.Author = "Test Author <author@example.com>"
+	cfg.Git.Remote = remote
+	cfg.Git.Push = true
+	ctx := NewContext(cfg, io.Discard, executor.Real{})
+
+	dir := cfg.ProjectDir()
+	gitignore, err := renderFiles(newFileData(ctx), ".gitignore", "gitignore.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		".gitignore":       gitignore[0].Content,
+		".env.local":       "BETTER_AUTH_SECRET=secret\n",
+		"auth.db":          "sqlite",
+		"src/app/page.tsx": "export default function Page() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	engine := &Engine{Steps: []Step{gitStep(), gitRemoteStep()}}
+	if _, err := engine.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	log := git(t, remote, "log", "--format=%an <%ae>|%cn <%ce>|%s", "HEAD")
+	want := cfg.Git.Author + "|" + cfg.Git.Author + "|" + DefaultCommitMessage
+	if log != want {
+		t.Errorf("remote log = %q, want the single commit %q", log, want)
+	}
+	tracked := git(t, dir, "ls-files")
+	for _, secret := range []string{".env.local", "auth.db"} {
+		if strings.Contains("\n"+tracked+"\n", "\n"+secret+"\n") {
+			t.Errorf("%s was committed", secret)
+		}
+	}
+	if !strings.Contains(tracked, "src/app/page.tsx") {
+		t.Errorf("page.tsx was not committed:\n%s", tracked)
+	}
+
+	// A retry finds origin already there and points it at the new remote
+	moved := filepath.Join(tmp, "moved.git")
+	git(t, tmp, "init", "--quiet", "--bare", moved)
+	ctx.Config.Git.Remote = moved
+	if _, err := (&Engine{Steps: []Step{gitRemoteStep()}}).Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := git(t, dir, "remote", "get-url", "origin"); got != moved {
+		t.Errorf("origin = %q, want %q", got, moved)
+	}
+}
+
+func TestEslintPrettierConfig(t *testing.T) {
+	tests := []struct {
+		name, config, want string
+	}{
+		{"compat typescript", `compat.extends("next/core-web-vitals", "next/typescript")`, `"next/typescript", "prettier"`},
+		{"compat javascript", `compat.extends("next/core-web-vitals")`, `"next/core-web-vitals", "prettier"`},
+		{"flat typescript", "  ...nextVitals,\n  ...nextTs,\n", "...nextTs,\n  prettier,"},
+		{"flat javascript", "  ...nextVitals,\n", "...nextVitals,\n  prettier,"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			rec := &executor.Recording{}
+			rec.WriteFile(filepath.Join(cfg.ProjectDir(), "eslint.config.mjs"), []byte(tt.config), 0644)
+			f, ok := eslintPrettierConfig(NewContext(cfg, io.Discard, rec))
+			if !ok {
+				t.Fatal("config was not recognised")
+			}
+			if !strings.Contains(f.Content, tt.want) {
+				t.Errorf("config is\n%s\nwant it to contain %q", f.Content, tt.want)
+			}
+			if strings.HasPrefix(tt.name, "flat") && !strings.HasPrefix(f.Content, `import prettier from "eslint-config-prettier/flat";`) {
+				t.Errorf("flat config does not import eslint-config-prettier:\n%s", f.Content)
+			}
+		})
+	}
+}
+
+func TestPackagesRecordProfile(t *testing.T) {
+	cfg := testConfig()
+	cfg.Versions = Profile{Name: "stable", Tag: "latest", Packages: map[string]string{"next": "15.5.4"}}
+	rec := &executor.Recording{}
+	if _, err := (&Engine{Steps: []Step{packagesStep()}}).Run(NewContext(cfg, io.Discard, rec)); err != nil {
+		t.Fatal(err)
+	}
+	cmds := rec.Commands()
+	last := cmds[len(cmds)-1]
+	if len(last.Args) != 4 || strings.Join(last.Args[:3], " ") != "pkg set --json" || !strings.HasPrefix(last.Args[3], "nextui=") {
+		t.Fatalf("last command is %s %v, want npm pkg set --json nextui=...", last.Name, last.Args)
+	}
+	var recorded struct {
+		Profile  string            `json:"profile"`
+		Tag      string            `json:"tag"`
+		Packages map[string]string `json:"packages"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(last.Args[3], "nextui=")), &recorded); err != nil {
+		t.Fatal(err)
+	}
+	if recorded.Profile != "stable" || recorded.Tag != "latest" || recorded.Packages["next"] != "15.5.4" {
+		t.Errorf("recorded %+v, want the stable profile and its pins", recorded)
+	}
+
+	// Without a profile nothing is recorded
+	rec = &executor.Recording{}
+	(&Engine{Steps: []Step{packagesStep()}}).Run(NewContext(testConfig(), io.Discard, rec))
+	if n := len(rec.Commands()); n != 1 {
+		t.Errorf("ran %d commands without a profile, want only npm install", n)
+	}
+}
+
+func TestThemeFallbackWarns(t *testing.T) {
+	cfg := testConfig()
+	cfg.Theme = "gone"
+	rec := &executor.Recording{Fail: func(c Command) error {
+		if len(c.Args) == 3 && c.Args[1] == "add" {
+			return errors.New("404 Not Found")
+		}
+		return nil
+	}}
+	ctx := NewContext(cfg, io.Discard, rec)
+	// The project exists, as create-next-app already ran
+	rec.WriteFile(filepath.Join(cfg.ProjectDir(), "package.json"), []byte("{}"), 0644)
+
+	results, err := (&Engine{Steps: []Step{shadcnInitStep()}}).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results[0].Warnings) != 2 {
+		t.Errorf("warnings %v, want the fallback and the failed re-add", results[0].Warnings)
+	}
+	if summary := Summary(cfg, results); strings.Contains(summary, "Theme applied") {
+		t.Errorf("summary reports the theme as applied:\n%s", summary)
+	}
+
+	results[0].Warnings = nil
+	if summary := Summary(cfg, results); !strings.Contains(summary, "Theme applied: gone") {
+		t.Errorf("summary does not report the applied theme:\n%s", summary)
+	}
+}
+
+func TestDryRunPrintsEdits(t *testing.T) {
+	cfg := Config{AppName: "myapp", ParentDir: t.TempDir()}
+	cfg.Next.ImportAlias = "~/*"
+	var out strings.Builder
+	ctx := NewContext(cfg, &out, executor.NewDryRun())
+
+	if _, ok := eslintPrettierConfig(ctx); ok {
+		t.Error("dry run produced an eslint config without reading one")
+	}
+	if _, ok := shadcnAliases(ctx); ok {
+		t.Error("dry run produced a components.json without reading one")
+	}
+	log := out.String()
+	if strings.Contains(log, "by hand") {
+		t.Errorf("dry run warns about a file it never created:\n%s", log)
+	}
+	for _, want := range []string{"Would add eslint-config-prettier to eslint.config.mjs", "Would point the components.json aliases at ~/"} {
+		if !strings.Contains(log, want) {
+			t.Errorf("log is missing %q:\n%s", want, log)
+		}
+	}
+}
+
+func TestGitHooksNeedRepository(t *testing.T) {
+	cfg := testConfig()
+	cfg.GitHooks = true
+	cfg.Git.Skip = true
+	rec := &executor.Recording{}
+	if _, err := (&Engine{Steps: Steps()}).Run(NewContext(cfg, io.Discard, rec)); err == nil {
+		t.Fatal("Run accepted git hooks with --no-git")
+	}
+	if n := len(rec.Commands()); n != 0 {
+		t.Errorf("ran %d commands before rejecting the config", n)
+	}
+}
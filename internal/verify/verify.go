@@ -0,0 +1,179 @@
+// Package verify checks that a generated project typechecks, lints and builds,
+// and pulls the error lines out of each tool's output.
+package verify
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/WillyV3/nextjs-templater/internal/executor"
+)
+
+// maxErrors caps how many parsed errors a result keeps
+const maxErrors = 10
+
+// Check is one verification command
+type Check struct {
+	Name    string
+	Title   string
+	Command executor.Command
+	// Parse extracts error lines from the command output
+	Parse func(output string) []string
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Name     string
+	Title    string
+	Passed   bool
+	Errors   []string
+	Duration time.Duration
+}
+
+// Checks returns the verification commands in run order; JavaScript projects
+// skip the typecheck. Commands run in the project directory; lint goes
+// through the project's own lint script since Next.js 16 dropped `next lint`
+// in favour of plain eslint.
+func Checks(typescript bool) []Check {
+	var checks []Check
+	if typescript {
+		checks = append(checks, Check{
+			Name:    "typecheck",
+			Title:   "Typecheck (tsc --noEmit)",
+			Command: executor.Command{Name: "npx", Args: []string{"tsc", "--noEmit"}},
+			Parse:   parseTsc,
+		})
+	}
+	return append(checks, []Check{
+		{
+			Name:    "lint",
+			Title:   "Lint (npm run lint)",
+			Command: executor.Command{Name: "npm", Args: []string{"run", "lint"}},
+			Parse:   parseLint,
+		},
+		{
+			Name:    "build",
+			Title:   "Production build (next build)",
+			Command: executor.Command{Name: "npx", Args: []string{"next", "build"}},
+			Parse:   parseBuild,
+		},
+	}...)
+}
+
+// NewResult turns a finished command into a result. Errors are only parsed for
+// failing commands; when nothing is recognised the last output line is kept.
+func NewResult(c Check, output string, err error, d time.Duration) Result {
+	r := Result{Name: c.Name, Title: c.Title, Passed: err == nil, Duration: d}
+	if err != nil {
+		r.Errors = c.Parse(output)
+		if len(r.Errors) == 0 {
+			r.Errors = []string{lastLine(output, err.Error())}
+		}
+	}
+	return r
+}
+
+// Failed returns the names of the checks that did not pass
+func Failed(results []Result) []string {
+	var failed []string
+	for _, r := range results {
+		if !r.Passed {
+			failed = append(failed, r.Name)
+		}
+	}
+	return failed
+}
+
+var (
+	ansiRe = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+	// src/app/page.tsx(12,5): error TS2322: ... (plain) or src/app/page.tsx:12:5 - error TS2322: ... (pretty)
+	tscRe       = regexp.MustCompile(`^(\S+)\((\d+),(\d+)\): error (TS\d+: .*)$`)
+	tscPrettyRe = regexp.MustCompile(`^(\S+):(\d+):(\d+) - error (TS\d+: .*)$`)
+
+	// eslint stylish output: "  12:5  error  'x' is defined but never used  no-unused-vars"
+	lintRe = regexp.MustCompile(`^\s+(\d+):(\d+)\s+error\s+(.*?)\s*$`)
+
+	// next build points at the file on its own line, then prints the error
+	buildLocRe = regexp.MustCompile(`^\.?/?\S+\.(?:tsx?|jsx?|mjs|css):\d+:\d+$`)
+	buildErrRe = regexp.MustCompile(`^(?:Type error|Module not found|Error|SyntaxError|ReferenceError|TypeError): `)
+)
+
+func lines(output string) []string {
+	output = ansiRe.ReplaceAllString(output, "")
+	return strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+}
+
+func parseTsc(output string) []string {
+	var errs []string
+	for _, line := range lines(output) {
+		line = strings.TrimSpace(line)
+		m := tscRe.FindStringSubmatch(line)
+		if m == nil {
+			m = tscPrettyRe.FindStringSubmatch(line)
+		}
+		if m != nil {
+			errs = appendErr(errs, m[1]+":"+m[2]+":"+m[3]+" "+m[4])
+		}
+	}
+	return errs
+}
+
+func parseLint(output string) []string {
+	var errs []string
+	file := ""
+	for _, line := range lines(output) {
+		if m := lintRe.FindStringSubmatch(line); m != nil {
+			errs = appendErr(errs, file+":"+m[1]+":"+m[2]+" "+m[3])
+			continue
+		}
+		// File headers are absolute paths on their own line
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(line, " ") && (strings.HasPrefix(trimmed, "/") || strings.HasPrefix(trimmed, "./")) {
+			file = trimmed
+		}
+	}
+	return errs
+}
+
+func parseBuild(output string) []string {
+	var errs []string
+	loc := ""
+	for _, line := range lines(output) {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case buildLocRe.MatchString(trimmed):
+			loc = trimmed
+		case buildErrRe.MatchString(trimmed):
+			if loc != "" {
+				trimmed = loc + " " + trimmed
+				loc = ""
+			}
+			errs = appendErr(errs, trimmed)
+		}
+	}
+	return errs
+}
+
+func appendErr(errs []string, e string) []string {
+	if len(errs) >= maxErrors {
+		return errs
+	}
+	for _, existing := range errs {
+		if existing == e {
+			return errs
+		}
+	}
+	return append(errs, e)
+}
+
+func lastLine(output, fallback string) string {
+	all := lines(output)
+	for i := len(all) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(all[i]); line != "" {
+			return line
+		}
+	}
+	return fallback
+}
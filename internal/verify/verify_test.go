@@ -0,0 +1,126 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParsers(t *testing.T) {
+	tests := []struct {
+		name   string
+		parse  func(string) []string
+		output string
+		want   []string
+	}{
+		{
+			"tsc plain", parseTsc,
+			"src/app/page.tsx(12,5): error TS2322: Type 'string' is not assignable to type 'number'.\n",
+			[]string{"src/app/page.tsx:12:5 TS2322: Type 'string' is not assignable to type 'number'."},
+		},
+		{
+			"tsc pretty", parseTsc,
+			"\x1b[96msrc/lib/auth.ts\x1b[0m:\x1b[93m3\x1b[0m:\x1b[93m10\x1b[0m - \x1b[91merror\x1b[0m TS2304: Cannot find name 'x'.\r\n\n3 x\n\nFound 1 error.\n",
+			[]string{"src/lib/auth.ts:3:10 TS2304: Cannot find name 'x'."},
+		},
+		{"tsc clean", parseTsc, "", nil},
+		{
+			"tsc duplicates", parseTsc,
+			"a.ts(1,1): error TS1: x\na.ts(1,1): error TS1: x\n",
+			[]string{"a.ts:1:1 TS1: x"},
+		},
+		{
+			"eslint stylish", parseLint,
+			"\n/work/app/src/app/page.tsx\n  4:7  error    'unused' is assigned a value but never used  @typescript-eslint/no-unused-vars\n  9:1  warning  Unexpected console statement  no-console\n\n✖ 2 problems (1 error, 1 warning)\n",
+			[]string{"/work/app/src/app/page.tsx:4:7 'unused' is assigned a value but never used  @typescript-eslint/no-unused-vars"},
+		},
+		{
+			"eslint relative", parseLint,
+			"./src/a.ts\n  1:1  error  Parsing error  \n",
+			[]string{"./src/a.ts:1:1 Parsing error"},
+		},
+		{
+			"next build type error", parseBuild,
+			"   Linting and checking validity of types ...\nFailed to compile.\n\n./src/app/page.tsx:5:3\nType error: Property 'foo' does not exist on type '{}'.\n",
+			[]string{"./src/app/page.tsx:5:3 Type error: Property 'foo' does not exist on type '{}'."},
+		},
+		{
+			"next build module", parseBuild,
+			"Module not found: Can't resolve '@/lib/missing'\n",
+			[]string{"Module not found: Can't resolve '@/lib/missing'"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.parse(tt.output); !equal(got, tt.want) {
+				t.Errorf("got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCapsErrors(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < maxErrors+5; i++ {
+		fmt.Fprintf(&b, "a.ts(%d,1): error TS1: x\n", i+1)
+	}
+	if got := len(parseTsc(b.String())); got != maxErrors {
+		t.Errorf("kept %d errors, want %d", got, maxErrors)
+	}
+}
+
+func TestNewResult(t *testing.T) {
+	check := Check{Name: "lint", Title: "Lint", Parse: parseLint}
+	tests := []struct {
+		name   string
+		output string
+		err    error
+		passed bool
+		errors []string
+	}{
+		{"passed", "all good\n", nil, true, nil},
+		{"parsed", "/a.ts\n  1:2  error  bad  rule\n", errors.New("exit status 1"), false, []string{"/a.ts:1:2 bad  rule"}},
+		{"last line", "something broke\n\nnpm ERR! Lifecycle script failed\n\n", errors.New("exit status 1"), false, []string{"npm ERR! Lifecycle script failed"}},
+		{"no output", "", errors.New("exit status 2"), false, []string{"exit status 2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewResult(check, tt.output, tt.err, 0)
+			if r.Passed != tt.passed || !equal(r.Errors, tt.errors) {
+				t.Errorf("passed %t errors %q, want %t and %q", r.Passed, r.Errors, tt.passed, tt.errors)
+			}
+		})
+	}
+}
+
+func TestChecks(t *testing.T) {
+	names := func(checks []Check) []string {
+		var out []string
+		for _, c := range checks {
+			out = append(out, c.Name)
+		}
+		return out
+	}
+	if got := names(Checks(true)); !equal(got, []string{"typecheck", "lint", "build"}) {
+		t.Errorf("TypeScript checks %v", got)
+	}
+	if got := names(Checks(false)); !equal(got, []string{"lint", "build"}) {
+		t.Errorf("JavaScript checks %v", got)
+	}
+	if got := Failed([]Result{{Name: "lint", Passed: true}, {Name: "build"}}); !equal(got, []string{"build"}) {
+		t.Errorf("Failed = %v", got)
+	}
+}
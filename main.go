@@ -2,24 +2,31 @@ package main
 
 import (
 	"bytes"
+	"context"
 	_ "embed"
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
-	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/WillyV3/nextjs-templater/internal/browse"
+	"github.com/WillyV3/nextjs-templater/internal/devserver"
+	"github.com/WillyV3/nextjs-templater/internal/diagnose"
+	"github.com/WillyV3/nextjs-templater/internal/executor"
+	"github.com/WillyV3/nextjs-templater/internal/scaffold"
+	"github.com/WillyV3/nextjs-templater/internal/templates"
+	"github.com/WillyV3/nextjs-templater/internal/verify"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/WillyV3/nextjs-templater/internal/templates"
 )
 
 //go:embed ascii/asciiArt.txt
@@ -43,16 +50,19 @@ const (
 	stepAppName step = iota
 	stepDirectory
 	stepTheme
+	stepNextOptions
 	stepAuthChoice
+	stepClerkOptions
+	stepTooling
+	stepExtras
+	stepReview
 	stepProgress
 	stepComplete
+	stepDevServer
 )
 
-type fileEntry struct {
-	Name  string
-	Path  string
-	IsDir bool
-}
+// fileEntry is a row of the directory browser
+type fileEntry = browse.Entry
 
 type model struct {
 	step           step
@@ -65,19 +75,84 @@ type model struct {
 	viewportEnd    int
 	theme          list.Model
 	authChoice     list.Model
+	toolingChoice  list.Model
 	progress       progress.Model
 	progress2      progress.Model
 	progress3      progress.Model
 	outputViewport viewport.Model
 	newDirInput    textinput.Model
 	searchInput    textinput.Model
+	jumpInput      textinput.Model
 	creatingNewDir bool
 	searching      bool
+	jumping        bool               // typing a path to go to (/ or g)
+	jumpErr        error              // why the typed path cannot be opened
+	jumpMatches    []string           // candidates of the last ambiguous tab completion
+	dirErr         error              // why the last directory could not be opened
+	deepSearch     bool               // search mode looks through the whole subtree
+	deepRunning    bool               // a subtree search is walking
+	deepSeq        int                // id of the latest subtree search; older results are dropped
+	deepCancel     context.CancelFunc // stops the running subtree search
+	loading        bool               // a directory listing is being read
+	loadPath       string             // directory being listed
+	loadSeq        int                // id of the latest listing; older pages are dropped
+	loadCancel     context.CancelFunc // stops the listing in progress
+	selectOnLoad   bool               // go on to the theme once the listing opens
+	lastClick      click              // spots double-clicks
+	restorePath    string             // entry to highlight again once a reload lists it
+	listOpts       browse.Options     // files, hidden entries and sort order of the listing
+	loadSpinner    spinner.Model      // turns while a listing loads
+	places         *browse.Places     // bookmarked and recent parent directories
+	picks          []browse.Pick      // quick picks shown above the listing
+	preview        browse.Preview     // children of the highlighted directory
+	previewPath    string             // directory the preview is loading or showing
+	previewSeq     int                // id of the latest preview; older ones are dropped
+	previewCancel  context.CancelFunc // stops the preview being loaded
+	dirProject     browse.Project     // what already lives in the current directory
 	err            error
 	output         string
 	useClerk       bool
 	useBetterAuth  bool
 	isRunning      bool
+	useScript      bool                  // run the legacy bash script instead of the step engine
+	dryRun         bool                  // generate with the dry-run executor (--dry-run)
+	verify         bool                  // typecheck, lint and build after generation (--verify)
+	checks         []verify.Result       // verification results of the last run
+	git            scaffold.GitOptions   // initial commit settings (--git-* flags)
+	versions       scaffold.Profile      // pinned package versions (--profile)
+	extras         []extraOption         // optional add-ons picked after auth
+	extrasCursor   int                   // selected row of the extras checklist
+	showPlan       bool                  // dry-run preview is visible on the review screen
+	runConfig      scaffold.Config       // config of the current or last run, used to resume it
+	initCmd        tea.Cmd               // started with the program, e.g. by `nextui resume`
+	diagnoses      []diagnose.Diagnosis  // known failure signatures found in the output
+	actionCursor   int                   // selected entry of the completion menu
+	viewingLog     bool                  // completion screen shows the full log
+	notice         string                // feedback from the last completion action
+	devServer      *devserver.Server     // dev server started from the completion menu
+	serverTicking  bool                  // a devServerTickMsg is in flight
+	steps          []scaffold.StepResult // live step status from the engine
+
+	// installation output viewport
+	outputContent   string          // cleaned output shown in the viewport, before search marks
+	outputSearch    textinput.Model // query typed after /
+	searchingOutput bool            // the query is being typed
+	outputMatches   []int           // lines of the output matching the query
+	outputMatch     int             // index in outputMatches of the current match
+	outputNotice    string          // feedback from the last copy
+
+	// create-next-app project options
+	nextOpts   scaffold.NextOptions
+	aliasInput textinput.Model
+	nextFocus  int
+	nextErr    error
+
+	// Clerk protected routes options
+	clerkScaffold  bool
+	clerkPublic    textinput.Model
+	clerkProtected textinput.Model
+	clerkOrgs      bool
+	clerkFocus     int
 
 	// Window size
 	width  int
@@ -87,7 +162,10 @@ type model struct {
 var (
 	// Shared buffers for real-time output (following Gum's pattern)
 	liveOutputBuf bytes.Buffer
-	executing     *exec.Cmd
+
+	// Step results reported by the engine while it runs
+	liveStepsMu sync.Mutex
+	liveSteps   []scaffold.StepResult
 
 	// Progress bar timing controls
 	progressTickInterval = time.Millisecond * 200 // Slower tick rate
@@ -108,6 +186,38 @@ var (
 
 	fileStyle = lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#008080"))
+
+	// Characters of a name matched by the search query
+	matchStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD93D")).Bold(true).Underline(true)
+
+	// The search match the output viewport is on
+	currentMatchStyle = lipgloss.NewStyle().Background(lipgloss.Color("#FFD93D")).Foreground(lipgloss.Color("0")).Bold(true)
+
+	// Broken symlinks and directories that can't be opened
+	lockedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	// Project tags in the browser and the warning about scaffolding inside one
+	tagStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#C792EA"))
+	warningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD93D"))
+
+	// Right-hand pane of the browser showing the highlighted directory
+	previewStyle = lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(lipgloss.Color("240")).
+			PaddingLeft(1)
+
+	// Diagnosis panel on the error screen
+	diagnosisStyle = lipgloss.NewStyle().
+			Border(lipgloss.ThickBorder()).
+			BorderForeground(lipgloss.Color("#FF6B6B")).
+			Padding(0, 1)
+	excerptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+
+	// Step list badges on the progress screen
+	stepPendingStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	stepRunningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("86")).Bold(true)
+	stepDoneStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#4ECDC4"))
+	stepFailedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B")).Bold(true)
 )
 
 func initialModel() model {
@@ -130,6 +240,19 @@ func initialModel() model {
 	searchInput.CharLimit = 100
 	searchInput.Width = 50
 
+	// Installation output search
+	outputSearch := textinput.New()
+	outputSearch.Placeholder = "Search the output..."
+	outputSearch.Prompt = "/"
+	outputSearch.CharLimit = 100
+	outputSearch.Width = 50
+
+	// Path jump input
+	jumpInput := textinput.New()
+	jumpInput.Placeholder = "~/code or /absolute/path"
+	jumpInput.CharLimit = 4096
+	jumpInput.Width = 50
+
 	// Theme list
 	var items []list.Item
 	for _, t := range template.NEXTJS_SHADCN_TEMPLATES {
@@ -159,6 +282,11 @@ func initialModel() model {
 		title: "Clerk",
 		desc:  "Complete authentication platform with social logins, MFA, and user management",
 	})
+	authItems = append(authItems, authItem{
+		id:    "clerk-protected",
+		title: "Clerk + Protected Routes",
+		desc:  "Clerk middleware with route matchers, a protected /dashboard, header user button and optional organizations",
+	})
 	authItems = append(authItems, authItem{
 		id:    "better-auth",
 		title: "Better Auth",
@@ -182,6 +310,43 @@ func initialModel() model {
 	authList.Title = "Choose authentication"
 	authList.SetShowHelp(false)
 
+	// Linting and formatting choice, reusing the auth list styling
+	toolingItems := []list.Item{
+		toolingItem{
+			id:    scaffold.ToolingESLintPrettier,
+			title: "ESLint + Prettier",
+			desc:  "Next.js ESLint rules with Prettier and Tailwind class sorting",
+		},
+		toolingItem{
+			id:    scaffold.ToolingBiome,
+			title: "Biome",
+			desc:  "One fast tool for linting, formatting and import sorting",
+		},
+	}
+	toolingList := list.New(toolingItems, authDelegate, 60, 20)
+	toolingList.Title = "Choose linting and formatting"
+	toolingList.SetShowHelp(false)
+
+	// Clerk route matcher inputs (comma separated)
+	clerkPublic := textinput.New()
+	clerkPublic.Placeholder = "/, /sign-in(.*), /sign-up(.*)"
+	clerkPublic.SetValue("/, /sign-in(.*), /sign-up(.*)")
+	clerkPublic.CharLimit = 300
+	clerkPublic.Width = 50
+
+	clerkProtected := textinput.New()
+	clerkProtected.Placeholder = "leave empty to protect every non-public route"
+	clerkProtected.SetValue("/dashboard(.*)")
+	clerkProtected.CharLimit = 300
+	clerkProtected.Width = 50
+
+	// Import alias of the project options
+	aliasInput := textinput.New()
+	aliasInput.Placeholder = scaffold.DefaultImportAlias
+	aliasInput.SetValue(scaffold.DefaultImportAlias)
+	aliasInput.CharLimit = 20
+	aliasInput.Width = 20
+
 	// Three stacked progress bars - only the top one shows percentage
 	prog := progress.New(
 		progress.WithScaledGradient("#FF6B6B", "#4ECDC4"),
@@ -218,9 +383,17 @@ func initialModel() model {
 		directory:      homeDir,
 		theme:          themeList,
 		authChoice:     authList,
+		toolingChoice:  toolingList,
 		outputViewport: vp,
 		newDirInput:    newDirInput,
 		searchInput:    searchInput,
+		jumpInput:      jumpInput,
+		outputSearch:   outputSearch,
+		loadSpinner:    spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(folderStyle)),
+		clerkPublic:    clerkPublic,
+		clerkProtected: clerkProtected,
+		aliasInput:     aliasInput,
+		extras:         defaultExtras(),
 		progress:       prog,
 		progress2:      prog2,
 		progress3:      prog3,
@@ -228,18 +401,49 @@ func initialModel() model {
 		height:         24,
 	}
 
-	m.loadDirectory(homeDir)
+	m.initCmd = m.loadDirectory(homeDir)
+	places, err := browse.LoadPlaces()
+	if err != nil {
+		m.dirErr = fmt.Errorf("can't read %s: %w", browse.Abbrev(browse.PlacesPath()), err)
+	}
+	m.places = places
+	m.refreshPicks()
 	return m
 }
 
-func (m *model) loadDirectory(path string) {
-	entries, err := os.ReadDir(path)
-	if err != nil {
-		return
-	}
+// maxPicks is how many bookmarks and recent directories the browser offers
+const maxPicks = 5
+
+const (
+	// previewLimit is how many children of the highlighted directory are
+	// listed; one more is read to tell whether there are others
+	previewLimit = 200
+	// previewMinWidth is the terminal width below which the preview is hidden
+	previewMinWidth = 90
+)
 
+// refreshPicks recomputes the quick picks after the places changed
+func (m *model) refreshPicks() {
+	m.picks = m.places.Picks(time.Now(), maxPicks)
+}
+
+// showPicks reports whether the quick picks are on screen
+func (m model) showPicks() bool {
+	return len(m.picks) > 0 && !m.searching && !m.jumping && !m.creatingNewDir
+}
+
+// showDirectory switches the browser to path, listing only its parent
+// until the entries are loaded
+func (m *model) showDirectory(path string, project browse.Project) {
+	m.restorePath = ""
+	if path == m.directory {
+		// Reloaded, e.g. after a toggle; find the highlighted entry again
+		m.restorePath = m.highlightedPath()
+	}
 	m.files = []fileEntry{}
 	m.directory = path
+	m.dirProject = project
+	m.previewPath = "" // the highlighted folder may have changed on disk
 
 	// Add parent directory option if not root
 	if path != "/" && path != filepath.Dir(path) {
@@ -251,87 +455,120 @@ func (m *model) loadDirectory(path string) {
 		})
 	}
 
-	// Sort directories first, then files
-	sort.Slice(entries, func(i, j int) bool {
-		if entries[i].IsDir() != entries[j].IsDir() {
-			return entries[i].IsDir()
-		}
-		return entries[i].Name() < entries[j].Name()
-	})
-
-	for _, entry := range entries {
-		// Skip hidden files
-		if strings.HasPrefix(entry.Name(), ".") {
-			continue
-		}
-
-		m.files = append(m.files, fileEntry{
-			Name:  entry.Name(),
-			Path:  filepath.Join(path, entry.Name()),
-			IsDir: entry.IsDir(),
-		})
-	}
-
 	m.filteredFiles = m.files
 	m.cursor = 0
 	m.searching = false // Disable search when entering new folder
+	m.stopDeepSearch()
 	m.searchInput.Blur()
 	m.searchInput.SetValue("")
 	m.updateViewport()
 }
 
-// Fuzzy search function
-func fuzzyMatch(pattern, text string) bool {
-	pattern = strings.ToLower(pattern)
-	text = strings.ToLower(text)
+// highlightedPath is the path under the cursor, empty when nothing is listed
+func (m model) highlightedPath() string {
+	if m.cursor < 0 || m.cursor >= len(m.filteredFiles) {
+		return ""
+	}
+	return m.filteredFiles[m.cursor].Path
+}
 
-	if pattern == "" {
-		return true
+// firstEntry is the index in files after the ".." row, which stays on top
+func (m model) firstEntry() int {
+	if len(m.files) > 0 && m.files[0].Name == ".." {
+		return 1
 	}
+	return 0
+}
 
-	patternIdx := 0
-	for _, char := range text {
-		if patternIdx < len(pattern) && rune(pattern[patternIdx]) == char {
-			patternIdx++
-		}
+// addEntries merges a page of loaded entries into the listing, keeping the
+// highlighted entry under the cursor
+func (m *model) addEntries(page []fileEntry) {
+	if len(page) == 0 {
+		return
 	}
+	highlighted := m.highlightedPath()
+	if m.cursor == 0 && m.restorePath != "" {
+		highlighted = m.restorePath
+	}
+	top := m.firstEntry()
+	m.files = append(m.files[:top:top], browse.Merge(m.files[top:], page, m.listOpts.Sort)...)
+	m.refilter(highlighted)
+}
 
-	return patternIdx == len(pattern)
+// resort orders the listing by the current sort mode
+func (m *model) resort() {
+	highlighted := m.highlightedPath()
+	browse.Sort(m.files[m.firstEntry():], m.listOpts.Sort)
+	m.refilter(highlighted)
 }
 
-func (m *model) filterFiles() {
-	query := m.searchInput.Value()
-	if query == "" {
-		m.filteredFiles = m.files
-	} else {
-		m.filteredFiles = []fileEntry{}
-		for _, file := range m.files {
-			if fuzzyMatch(query, file.Name) {
-				m.filteredFiles = append(m.filteredFiles, file)
+// refilter applies the search to the changed listing and puts the cursor
+// back on path
+func (m *model) refilter(path string) {
+	if m.deepSearch {
+		return // the listing shows subtree matches, not this folder
+	}
+	m.filteredFiles = browse.Filter(m.searchInput.Value(), m.files)
+	for i, entry := range m.filteredFiles {
+		if entry.Path == path {
+			m.cursor = i
+			if path == m.restorePath {
+				m.restorePath = ""
 			}
+			break
 		}
 	}
+	m.updateViewport()
+}
+
+// listMode describes what the listing shows, for the help line
+func (m model) listMode() string {
+	mode := "folders"
+	if m.listOpts.Files {
+		mode = "folders + files"
+	}
+	if m.listOpts.Hidden {
+		mode += " · hidden"
+	}
+	return mode + " · by " + m.listOpts.Sort.String()
+}
+
+func (m *model) filterFiles() {
+	// Best matches first, as the user types
+	m.filteredFiles = browse.Filter(m.searchInput.Value(), m.files)
 
 	// Reset cursor and update viewport
 	m.cursor = 0
 	m.updateViewport()
 }
 
-func (m *model) updateViewport() {
-	if len(m.filteredFiles) == 0 {
-		return
-	}
-
+// listHeight is how many rows of the listing fit on screen
+func (m model) listHeight() int {
 	// Calculate available height accounting for bordered title and other elements
 	// Bordered title takes ~4-5 lines, plus margins and controls
 	availableHeight := m.height - 12 // More conservative for bordered title
-	if m.creatingNewDir || m.searching {
+	if m.creatingNewDir || m.searching || m.jumping {
 		availableHeight -= 2 // Extra space for input
 	}
+	if m.showPicks() {
+		availableHeight -= len(m.picks) + 1
+	}
+	if projectWarning(m.dirProject) != "" {
+		availableHeight--
+	}
 	// Ensure minimum usable height
 	if availableHeight < 3 {
 		availableHeight = 3
 	}
+	return availableHeight
+}
+
+func (m *model) updateViewport() {
+	if len(m.filteredFiles) == 0 {
+		return
+	}
+
+	availableHeight := m.listHeight()
 
 	// Ensure cursor is within bounds
 	if m.cursor >= len(m.filteredFiles) {
@@ -359,6 +596,50 @@ func (m *model) updateViewport() {
 	}
 }
 
+// stopPreview cancels the preview being loaded and clears the pane
+func (m *model) stopPreview() {
+	if m.previewCancel != nil {
+		m.previewCancel()
+		m.previewCancel = nil
+	}
+	m.previewSeq++
+	m.previewPath = ""
+	m.preview = browse.Preview{}
+}
+
+// previewMsg carries the children of the highlighted directory
+type previewMsg struct {
+	seq     int
+	preview browse.Preview
+}
+
+// loadPreview starts reading the highlighted directory for the preview pane
+// and its project tags when the highlight moved to another directory. While another folder is
+// being opened nothing is read, since the highlight is about to go away.
+func (m *model) loadPreview() tea.Cmd {
+	path := ""
+	opening := m.loading && m.loadPath != m.directory
+	if m.step == stepDirectory && !opening && m.cursor >= 0 && m.cursor < len(m.filteredFiles) {
+		if entry := m.filteredFiles[m.cursor]; entry.IsDir && !entry.Broken && !entry.Locked {
+			path = entry.Path
+		}
+	}
+	if path == m.previewPath {
+		return nil
+	}
+	m.stopPreview()
+	m.previewPath = path
+	if path == "" {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.previewCancel = cancel
+	seq := m.previewSeq
+	return func() tea.Msg {
+		return previewMsg{seq: seq, preview: browse.LoadPreview(ctx, path, previewLimit)}
+	}
+}
+
 type themeItem struct {
 	id    int
 	title string
@@ -379,6 +660,16 @@ func (a authItem) Title() string       { return a.title }
 func (a authItem) Description() string { return a.desc }
 func (a authItem) FilterValue() string { return a.title }
 
+type toolingItem struct {
+	id    scaffold.Tooling
+	title string
+	desc  string
+}
+
+func (t toolingItem) Title() string       { return t.title }
+func (t toolingItem) Description() string { return t.desc }
+func (t toolingItem) FilterValue() string { return t.title }
+
 // stripAnsiCodes removes ANSI escape sequences from text
 func stripAnsiCodes(input string) string {
 	// Regular expression to match ANSI escape sequences
@@ -421,16 +712,189 @@ func getAsciiArt() string {
 }
 
 
+// clerkOptions carries the Clerk protected routes choices to the script
+type clerkOptions struct {
+	Scaffold        bool
+	PublicRoutes    string
+	ProtectedRoutes string
+	Organizations   bool
+}
+
+// extraOption is an optional add-on in the extras checklist
+type extraOption struct {
+	id      string
+	title   string
+	desc    string
+	checked bool
+}
+
+// defaultExtras lists the add-ons offered after the auth choice
+func defaultExtras() []extraOption {
+	return []extraOption{
+		{id: "docker", title: "Docker", desc: "Multi-stage Dockerfile (standalone output), .dockerignore, docker-compose.yml"},
+		{id: "vitest", title: "Vitest + Testing Library", desc: "Unit tests with a sample shadcn Button test (npm test)"},
+		{id: "playwright", title: "Playwright", desc: "End-to-end tests with a sample home page test (npm run test:e2e)"},
+		{id: "git-hooks", title: "Git hooks", desc: "husky + lint-staged: lint and format staged files before every commit"},
+		{id: "github-actions", title: "GitHub Actions", desc: "CI workflow: install, lint, typecheck, test and build"},
+		{id: "gitlab-ci", title: "GitLab CI", desc: "The same pipeline as .gitlab-ci.yml"},
+	}
+}
+
+// extraChecked reports whether the add-on with id was picked
+func (m model) extraChecked(id string) bool {
+	for _, e := range m.extras {
+		if e.id == id {
+			return e.checked
+		}
+	}
+	return false
+}
+
 type progressMsg float64
 
 type outputUpdateMsg struct{}
 
+// planMsg carries a dry-run preview back to the review screen
+type planMsg struct {
+	output string
+	err    error
+}
+
 type completeMsg struct {
 	output string
 	err    error
+	steps  []scaffold.StepResult
+	checks []verify.Result
+}
+
+// tweakcnTheme returns the tweakcn theme name for a template title, empty for the default theme
+func tweakcnTheme(title string) string {
+	for _, t := range template.NEXTJS_SHADCN_TEMPLATES {
+		if t.Title == title && t.Id != 0 {
+			return strings.TrimPrefix(t.Title, "nextjs-")
+		}
+	}
+	return ""
+}
+
+// scaffoldConfig collects the wizard choices for the step engine
+func (m model) scaffoldConfig() scaffold.Config {
+	cfg := scaffold.Config{
+		AppName:   m.appName.Value(),
+		ParentDir: m.directory,
+		Auth:      scaffold.AuthNone,
+		Verify:    m.verify,
+		Git:       m.git,
+		Docker:    m.extraChecked("docker"),
+		GitHooks:  m.extraChecked("git-hooks"),
+		Versions:  m.versions,
+		Testing: scaffold.TestingOptions{
+			Vitest:     m.extraChecked("vitest"),
+			Playwright: m.extraChecked("playwright"),
+		},
+		CI: scaffold.CIOptions{
+			GitHub: m.extraChecked("github-actions"),
+			GitLab: m.extraChecked("gitlab-ci"),
+		},
+	}
+	cfg.Next = m.nextOpts
+	if alias := strings.TrimSpace(m.aliasInput.Value()); alias != scaffold.DefaultImportAlias {
+		cfg.Next.ImportAlias = alias
+	}
+	if themeSelected, ok := m.theme.SelectedItem().(themeItem); ok {
+		cfg.Theme = tweakcnTheme(themeSelected.title)
+	}
+	if tooling, ok := m.toolingChoice.SelectedItem().(toolingItem); ok {
+		cfg.Tooling = tooling.id
+	}
+	switch {
+	case m.useClerk:
+		cfg.Auth = scaffold.AuthClerk
+		cfg.Clerk = scaffold.ClerkOptions{
+			Scaffold:        m.clerkScaffold,
+			PublicRoutes:    scaffold.SplitRoutes(m.clerkPublic.Value()),
+			ProtectedRoutes: scaffold.SplitRoutes(m.clerkProtected.Value()),
+			Organizations:   m.clerkOrgs,
+		}
+	case m.useBetterAuth:
+		cfg.Auth = scaffold.AuthBetterAuth
+	}
+	return cfg
+}
+
+// snapshotSteps copies the live step results for the UI
+func snapshotSteps() []scaffold.StepResult {
+	liveStepsMu.Lock()
+	defer liveStepsMu.Unlock()
+	return append([]scaffold.StepResult(nil), liveSteps...)
+}
+
+// runPipeline runs the native step engine, reporting step results as it goes.
+// Steps in completed are skipped and the run stops after until, if set.
+func runPipeline(cfg scaffold.Config, ex executor.Executor, completed []string, until string) tea.Cmd {
+	return func() tea.Msg {
+		engine := &scaffold.Engine{
+			Steps:     scaffold.Steps(),
+			Completed: completed,
+			Until:     until,
+		}
+
+		// Seed the live step list so pending steps show up immediately
+		liveStepsMu.Lock()
+		liveSteps = nil
+		for _, s := range engine.Plan(cfg) {
+			liveSteps = append(liveSteps, scaffold.StepResult{Name: s.Name, Title: s.Title})
+		}
+		liveStepsMu.Unlock()
+
+		engine.OnResult = func(r scaffold.StepResult) {
+			liveStepsMu.Lock()
+			defer liveStepsMu.Unlock()
+			for i := range liveSteps {
+				if liveSteps[i].Name == r.Name {
+					liveSteps[i] = r
+				}
+			}
+		}
+
+		liveOutputBuf.Reset()
+		var outputBuffer strings.Builder
+		out := io.MultiWriter(&outputBuffer, &liveOutputBuf)
+
+		fmt.Fprintf(out, "=== EXECUTION INFO ===\nApp name: %s\nDirectory: %s\nTheme: %s\nAuth: %s\n",
+			cfg.AppName,
+			cfg.ParentDir,
+			cfg.Theme,
+			cfg.Auth)
+		if cfg.Clerk.Scaffold {
+			fmt.Fprintf(out, "Clerk: public=%q protected=%q organizations=%t\n",
+				cfg.Clerk.PublicRoutes,
+				cfg.Clerk.ProtectedRoutes,
+				cfg.Clerk.Organizations)
+		}
+
+		ctx := scaffold.NewContext(cfg, out, ex)
+		// Tools drop their colours when writing to a pipe; the viewport shows them
+		ctx.SetEnv("FORCE_COLOR", "1")
+		ctx.SetEnv("npm_config_color", "always")
+		steps, err := engine.Run(ctx)
+		if err != nil {
+			fmt.Fprintf(&outputBuffer, "\n❌ EXECUTION FAILED: %v\n", err)
+		} else {
+			fmt.Fprintf(out, "\n%s", scaffold.Summary(cfg, steps))
+			outputBuffer.WriteString("\n✅ EXECUTION COMPLETED SUCCESSFULLY\n")
+		}
+
+		return completeMsg{
+			output: outputBuffer.String(),
+			err:    err,
+			steps:  steps,
+			checks: ctx.Verification,
+		}
+	}
 }
 
-func runScript(appName, directory, theme string, useClerk, useBetterAuth bool) tea.Cmd {
+func runScript(appName, directory, theme string, useClerk, useBetterAuth bool, clerk clerkOptions, ex executor.Executor) tea.Cmd {
 	return func() tea.Msg {
 		// Find the theme template
 		var selectedTemplate template.Item
@@ -446,7 +910,7 @@ func runScript(appName, directory, theme string, useClerk, useBetterAuth bool) t
 		var outputBuffer strings.Builder
 
 		// Check if bash exists
-		if _, err := exec.LookPath("bash"); err != nil {
+		if _, err := ex.LookPath("bash"); err != nil {
 			errorMsg := fmt.Sprintf("❌ DEPENDENCY ERROR: bash not found in PATH\nError: %v\n", err)
 			outputBuffer.WriteString(errorMsg)
 			return completeMsg{
@@ -456,7 +920,7 @@ func runScript(appName, directory, theme string, useClerk, useBetterAuth bool) t
 		}
 
 		// Check if node exists
-		if _, err := exec.LookPath("node"); err != nil {
+		if _, err := ex.LookPath("node"); err != nil {
 			warningMsg := fmt.Sprintf("⚠️  WARNING: node not found in PATH\nError: %v\nScript may fail if Node.js is required\n\n", err)
 			outputBuffer.WriteString(warningMsg)
 			liveOutputBuf.WriteString(warningMsg)
@@ -470,29 +934,44 @@ func runScript(appName, directory, theme string, useClerk, useBetterAuth bool) t
 		}
 
 		// Log execution info
-		initialMsg := fmt.Sprintf("=== EXECUTION INFO ===\nTheme: %s\nApp name: %s\nDirectory: %s\nAuth: Clerk=%t, BetterAuth=%t\nTheme name: %s\n\n",
+		initialMsg := fmt.Sprintf("=== EXECUTION INFO ===\nTheme: %s\nApp name: %s\nDirectory: %s\nAuth: Clerk=%t, BetterAuth=%t\nTheme name: %s\n",
 			selectedTemplate.Title,
 			appName,
 			directory,
 			useClerk,
 			useBetterAuth,
 			themeName)
+		if clerk.Scaffold {
+			initialMsg += fmt.Sprintf("Clerk: public=[%s] protected=[%s] organizations=%t\n",
+				clerk.PublicRoutes,
+				clerk.ProtectedRoutes,
+				clerk.Organizations)
+		}
+		initialMsg += "\n"
 		outputBuffer.WriteString(initialMsg)
 		liveOutputBuf.WriteString(initialMsg)
 
 		// Execute the embedded script by piping it to bash with arguments
-		executing = exec.Command("bash", "-s", "--", appName, directory, themeName, fmt.Sprintf("%t", useClerk), fmt.Sprintf("%t", useBetterAuth))
-		executing.Dir = directory
-
-		// Pipe the embedded script to stdin
-		executing.Stdin = strings.NewReader(shellScriptContent)
+		script := executor.Command{
+			Dir:  directory,
+			Name: "bash",
+			Args: []string{"-s", "--", appName, directory, themeName,
+				fmt.Sprintf("%t", useClerk),
+				fmt.Sprintf("%t", useBetterAuth),
+				fmt.Sprintf("%t", clerk.Scaffold),
+				clerk.PublicRoutes,
+				clerk.ProtectedRoutes,
+				fmt.Sprintf("%t", clerk.Organizations)},
+			Input: shellScriptContent,
+			Env:   []string{"FORCE_COLOR=1", "npm_config_color=always"},
+		}
 
 		// Use MultiWriter to write to both the final output buffer and the live buffer
-		executing.Stdout = io.MultiWriter(&outputBuffer, &liveOutputBuf)
-		executing.Stderr = io.MultiWriter(&outputBuffer, &liveOutputBuf)
+		out := io.MultiWriter(&outputBuffer, &liveOutputBuf)
+		fmt.Fprintf(out, "$ %s\n", script)
 
 		// Run the command
-		err := executing.Run()
+		err := ex.Run(script, out, out)
 
 		// Add execution result info
 		if err != nil {
@@ -516,12 +995,44 @@ func tickProgress() tea.Cmd {
 	})
 }
 
+// devServerTickMsg refreshes the dev server view
+type devServerTickMsg struct{}
+
+// devServerMsg reports the result of a start, stop or restart
+type devServerMsg struct {
+	err error
+}
+
+func tickDevServer() tea.Cmd {
+	return tea.Tick(time.Millisecond*200, func(t time.Time) tea.Msg {
+		return devServerTickMsg{}
+	})
+}
+
 func tickOutputUpdate() tea.Cmd {
 	return tea.Tick(time.Millisecond*200, func(t time.Time) tea.Msg {
 		return outputUpdateMsg{}
 	})
 }
 
+// resumePipeline continues a run from the state recorded in the project.
+// A project that was never created (or was rolled back) simply starts over.
+func resumePipeline(cfg scaffold.Config, ex executor.Executor, until string) tea.Cmd {
+	return func() tea.Msg {
+		var completed []string
+		state, err := scaffold.LoadState(cfg.ProjectDir())
+		if err != nil {
+			if ex.Exists(cfg.ProjectDir()) {
+				return completeMsg{output: err.Error() + "\n", err: err}
+			}
+		} else {
+			cfg = state.Config
+			completed = state.Completed
+		}
+		return runPipeline(cfg, ex, completed, until)()
+	}
+}
+
 func (m model) Init() tea.Cmd {
-	return textinput.Blink
+	return tea.Batch(textinput.Blink, m.initCmd)
 }
@@ -67,9 +67,13 @@ PROJECT_PATH="${2:-$(pwd)}"
 THEME="${3:-}"
 USE_CLERK="${4:-false}"
 USE_BETTER_AUTH="${5:-false}"
+CLERK_SCAFFOLD="${6:-false}"
+CLERK_PUBLIC_ROUTES="${7-/,/sign-in(.*),/sign-up(.*)}"
+CLERK_PROTECTED_ROUTES="${8-/dashboard(.*)}"
+CLERK_ORGS="${9:-false}"
 
 if [ -z "$PROJECT_NAME" ]; then
-    echo "Usage: $0 <project-name> [path] [theme]"
+    echo "Usage: $0 <project-name> [path] [theme] [clerk] [better-auth] [clerk-scaffold] [public-routes] [protected-routes] [clerk-orgs]"
     echo ""
     echo "Available themes from tweakcn.com:"
     echo "  modern-minimal, violet-bloom, t3-chat, mocha-mousse, amethyst-haze,"
@@ -136,8 +140,290 @@ if ! yes | npx shadcn@latest add --all; then
     echo "⚠️  Some components may have failed to install, but continuing..."
 fi
 
+# Turn a comma separated route list into quoted entries for createRouteMatcher
+route_list() {
+    local out="" route routes
+    # read splits without pathname expansion, so /api/* stays as typed
+    IFS=',' read -ra routes <<< "$1"
+    for route in "${routes[@]}"; do
+        # Trim surrounding whitespace, then escape the JS string
+        route="${route#"${route%%[![:space:]]*}"}"
+        route="${route%"${route##*[![:space:]]}"}"
+        route="${route//\\/\\\\}"
+        route="${route//\"/\\\"}"
+        if [ -n "$route" ]; then
+            out="$out${out:+, }\"$route\""
+        fi
+    done
+    echo "$out"
+}
+
+# Escape a sed replacement: backslashes, the | delimiter and &
+sed_escape() {
+    printf '%s' "$1" | sed -e 's/[\\|&]/\\&/g'
+}
+
 # Add authentication if requested
-if [ "$USE_CLERK" = "true" ]; then
+if [ "$USE_CLERK" = "true" ] && [ "$CLERK_SCAFFOLD" = "true" ]; then
+    echo "Installing Clerk with protected routes..."
+    npm install @clerk/nextjs
+
+    PUBLIC_MATCHERS=$(route_list "$CLERK_PUBLIC_ROUTES")
+    PROTECTED_MATCHERS=$(route_list "$CLERK_PROTECTED_ROUTES")
+
+    # An empty protected list means every non-public route requires sign in
+    if [ -z "$PROTECTED_MATCHERS" ]; then
+        PROTECTED_DECL=""
+        PROTECT_CHECK="!isPublicRoute(req)"
+    else
+        PROTECTED_DECL="const isProtectedRoute = createRouteMatcher([$PROTECTED_MATCHERS]);"
+        PROTECT_CHECK="!isPublicRoute(req) && isProtectedRoute(req)"
+    fi
+
+    echo "Creating Clerk middleware..."
+    if [ "$CLERK_ORGS" = "true" ]; then
+        cat > src/middleware.ts << 'EOF'
+import { clerkMiddleware, createRouteMatcher } from "@clerk/nextjs/server";
+import { NextResponse } from "next/server";
+
+const isPublicRoute = createRouteMatcher([__PUBLIC__]);
+__PROTECTED_DECL__
+const isOrgSelectionRoute = createRouteMatcher(["/org-selection(.*)"]);
+
+export default clerkMiddleware(async (auth, req) => {
+  if (__PROTECT_CHECK__) {
+    const { userId, orgId, redirectToSignIn } = await auth();
+    if (!userId) {
+      return redirectToSignIn();
+    }
+    if (!orgId && !isOrgSelectionRoute(req)) {
+      return NextResponse.redirect(new URL("/org-selection", req.url));
+    }
+  }
+});
+
+export const config = {
+  matcher: [
+    // Skip Next.js internals and all static files, unless found in search params
+    "/((?!_next|[^?]*\\.(?:html?|css|js(?!on)|jpe?g|webp|png|gif|svg|ttf|woff2?|ico|csv|docx?|xlsx?|zip|webmanifest)).*)",
+    // Always run for API routes
+    "/(api|trpc)(.*)",
+  ],
+};
+EOF
+    else
+        cat > src/middleware.ts << 'EOF'
+import { clerkMiddleware, createRouteMatcher } from "@clerk/nextjs/server";
+
+const isPublicRoute = createRouteMatcher([__PUBLIC__]);
+__PROTECTED_DECL__
+
+export default clerkMiddleware(async (auth, req) => {
+  if (__PROTECT_CHECK__) {
+    await auth.protect();
+  }
+});
+
+export const config = {
+  matcher: [
+    // Skip Next.js internals and all static files, unless found in search params
+    "/((?!_next|[^?]*\\.(?:html?|css|js(?!on)|jpe?g|webp|png|gif|svg|ttf|woff2?|ico|csv|docx?|xlsx?|zip|webmanifest)).*)",
+    // Always run for API routes
+    "/(api|trpc)(.*)",
+  ],
+};
+EOF
+    fi
+    sed -e "s|__PUBLIC__|$(sed_escape "$PUBLIC_MATCHERS")|" \
+        -e "s|__PROTECTED_DECL__|$(sed_escape "$PROTECTED_DECL")|" \
+        -e "s|__PROTECT_CHECK__|$(sed_escape "$PROTECT_CHECK")|" \
+        src/middleware.ts > src/middleware.ts.tmp
+    mv src/middleware.ts.tmp src/middleware.ts
+
+    echo "Creating header with user button..."
+    mkdir -p src/components
+    ORG_IMPORT=""
+    ORG_SWITCHER=""
+    if [ "$CLERK_ORGS" = "true" ]; then
+        ORG_IMPORT="
+  OrganizationSwitcher,"
+        ORG_SWITCHER="
+            <OrganizationSwitcher afterSelectOrganizationUrl=\"/dashboard\" />"
+    fi
+    cat > src/components/site-header.tsx << EOF
+import Link from "next/link";
+import {$ORG_IMPORT
+  SignedIn,
+  SignedOut,
+  SignInButton,
+  SignUpButton,
+  UserButton,
+} from "@clerk/nextjs";
+import { Button } from "@/components/ui/button";
+
+export function SiteHeader() {
+  return (
+    <header className="border-b">
+      <div className="container mx-auto flex h-14 items-center justify-between px-4">
+        <Link href="/" className="font-semibold">
+          $PROJECT_NAME
+        </Link>
+        <nav className="flex items-center gap-2">
+          <SignedOut>
+            <SignInButton>
+              <Button variant="ghost">Sign in</Button>
+            </SignInButton>
+            <SignUpButton>
+              <Button>Sign up</Button>
+            </SignUpButton>
+          </SignedOut>
+          <SignedIn>
+            <Button asChild variant="ghost">
+              <Link href="/dashboard">Dashboard</Link>
+            </Button>$ORG_SWITCHER
+            <UserButton />
+          </SignedIn>
+        </nav>
+      </div>
+    </header>
+  );
+}
+EOF
+
+    echo "Wrapping layout in ClerkProvider..."
+    cat > src/app/layout.tsx << 'EOF'
+import type { Metadata } from "next";
+import { Geist, Geist_Mono } from "next/font/google";
+import { ClerkProvider } from "@clerk/nextjs";
+import { SiteHeader } from "@/components/site-header";
+import "./globals.css";
+
+const geistSans = Geist({
+  variable: "--font-geist-sans",
+  subsets: ["latin"],
+});
+
+const geistMono = Geist_Mono({
+  variable: "--font-geist-mono",
+  subsets: ["latin"],
+});
+
+export const metadata: Metadata = {
+  title: "Create Next App",
+  description: "Generated by create next app",
+};
+
+export default function RootLayout({
+  children,
+}: Readonly<{
+  children: React.ReactNode;
+}>) {
+  return (
+    <ClerkProvider>
+      <html lang="en">
+        <body
+          className={`${geistSans.variable} ${geistMono.variable} antialiased`}
+        >
+          <SiteHeader />
+          {children}
+        </body>
+      </html>
+    </ClerkProvider>
+  );
+}
+EOF
+
+    echo "Creating sign-in and sign-up pages..."
+    mkdir -p "src/app/sign-in/[[...sign-in]]" "src/app/sign-up/[[...sign-up]]"
+    cat > 'src/app/sign-in/[[...sign-in]]/page.tsx' << 'EOF'
+import { SignIn } from "@clerk/nextjs";
+
+export default function SignInPage() {
+  return (
+    <main className="flex min-h-[calc(100vh-3.5rem)] items-center justify-center">
+      <SignIn />
+    </main>
+  );
+}
+EOF
+    cat > 'src/app/sign-up/[[...sign-up]]/page.tsx' << 'EOF'
+import { SignUp } from "@clerk/nextjs";
+
+export default function SignUpPage() {
+  return (
+    <main className="flex min-h-[calc(100vh-3.5rem)] items-center justify-center">
+      <SignUp />
+    </main>
+  );
+}
+EOF
+
+    echo "Creating protected dashboard page..."
+    mkdir -p src/app/dashboard
+    cat > src/app/dashboard/page.tsx << 'EOF'
+import { auth, currentUser } from "@clerk/nextjs/server";
+import {
+  Card,
+  CardContent,
+  CardDescription,
+  CardHeader,
+  CardTitle,
+} from "@/components/ui/card";
+
+export default async function DashboardPage() {
+  const { orgId, orgSlug } = await auth();
+  const user = await currentUser();
+
+  return (
+    <main className="container mx-auto px-4 py-10">
+      <Card>
+        <CardHeader>
+          <CardTitle>Dashboard</CardTitle>
+          <CardDescription>Only signed in users can see this page.</CardDescription>
+        </CardHeader>
+        <CardContent className="space-y-1 text-sm">
+          <p>Signed in as {user?.primaryEmailAddress?.emailAddress ?? user?.id}</p>
+          {orgId && <p>Active organization: {orgSlug ?? orgId}</p>}
+        </CardContent>
+      </Card>
+    </main>
+  );
+}
+EOF
+
+    if [ "$CLERK_ORGS" = "true" ]; then
+        echo "Creating organization selection page..."
+        mkdir -p src/app/org-selection
+        cat > src/app/org-selection/page.tsx << 'EOF'
+import { OrganizationList } from "@clerk/nextjs";
+
+export default function OrgSelectionPage() {
+  return (
+    <main className="flex min-h-[calc(100vh-3.5rem)] items-center justify-center">
+      <OrganizationList
+        hidePersonal
+        afterSelectOrganizationUrl="/dashboard"
+        afterCreateOrganizationUrl="/dashboard"
+      />
+    </main>
+  );
+}
+EOF
+    fi
+
+    echo "Creating .env.local file..."
+    cat > .env.local << 'EOF'
+# Clerk Configuration
+# Leave the keys unset to run in Clerk keyless mode during development
+# NEXT_PUBLIC_CLERK_PUBLISHABLE_KEY=pk_test_...
+# CLERK_SECRET_KEY=sk_test_...
+
+NEXT_PUBLIC_CLERK_SIGN_IN_URL=/sign-in
+NEXT_PUBLIC_CLERK_SIGN_UP_URL=/sign-up
+EOF
+
+    echo "Clerk setup complete"
+elif [ "$USE_CLERK" = "true" ]; then
     echo "Installing Clerk authentication quickstart..."
     yes | npx shadcn@latest add @clerk/nextjs-quickstart
 elif [ "$USE_BETTER_AUTH" = "true" ]; then
@@ -217,7 +503,15 @@ echo "✅ Done. Project at: $FULL_PATH"
 if [ ! -z "$THEME" ]; then
     echo "   Theme applied: $THEME"
 fi
-if [ "$USE_CLERK" = "true" ]; then
+if [ "$USE_CLERK" = "true" ] && [ "$CLERK_SCAFFOLD" = "true" ]; then
+    echo "   Clerk authentication: Installed with middleware and protected /dashboard"
+    echo "   Public routes: $CLERK_PUBLIC_ROUTES"
+    echo "   Protected routes: ${CLERK_PROTECTED_ROUTES:-all non-public routes}"
+    if [ "$CLERK_ORGS" = "true" ]; then
+        echo "   Organizations: Enabled (turn them on in the Clerk dashboard)"
+    fi
+    echo "   Set CLERK_SECRET_KEY and NEXT_PUBLIC_CLERK_PUBLISHABLE_KEY in .env.local when leaving keyless mode"
+elif [ "$USE_CLERK" = "true" ]; then
     echo "   Clerk authentication: Installed"
     echo "   Don't forget to set your CLERK_SECRET_KEY and NEXT_PUBLIC_CLERK_PUBLISHABLE_KEY"
 elif [ "$USE_BETTER_AUTH" = "true" ]; then
@@ -1,15 +1,44 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
-	tea "github.com/charmbracelet/bubbletea"
+	"github.com/WillyV3/nextjs-templater/internal/browse"
+	"github.com/WillyV3/nextjs-templater/internal/devserver"
+	"github.com/WillyV3/nextjs-templater/internal/diagnose"
+	"github.com/WillyV3/nextjs-templater/internal/executor"
+	"github.com/WillyV3/nextjs-templater/internal/logview"
+	"github.com/WillyV3/nextjs-templater/internal/scaffold"
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
 )
 
+// Update handles msg, then starts loading the preview of whatever ended up
+// highlighted
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	if m, ok := next.(model); ok {
+		if preview := m.loadPreview(); preview != nil {
+			return m, tea.Batch(cmd, preview)
+		}
+		return m, cmd
+	}
+	return next, cmd
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -30,9 +59,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.appName.Width = inputWidth
 		m.newDirInput.Width = inputWidth
 		m.searchInput.Width = inputWidth
+		m.jumpInput.Width = inputWidth
+		m.outputSearch.Width = inputWidth
+		m.clerkPublic.Width = inputWidth
+		m.clerkProtected.Width = inputWidth
 		// Resize output viewport
 		m.outputViewport.Width = msg.Width - 4
-		m.outputViewport.Height = msg.Height - 12
+		m.outputViewport.Height = msg.Height - 14 // Leave room for the step list
 		// Calculate list height accounting for bordered titles and controls
 		listHeight := msg.Height - 12 // Account for bordered title + margins + controls
 		if listHeight < 5 {
@@ -40,6 +73,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.theme.SetSize(msg.Width-4, listHeight)
 		m.authChoice.SetSize(msg.Width-4, listHeight)
+		m.toolingChoice.SetSize(msg.Width-4, listHeight)
 		// Update viewport for file browser
 		m.updateViewport()
 
@@ -64,6 +98,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.searching {
 				switch msg.String() {
 				case "enter":
+					if m.deepSearch {
+						// Open the highlighted match and select it
+						if m.cursor < len(m.filteredFiles) {
+							return m, m.selectDirectory(m.filteredFiles[m.cursor].Path)
+						}
+						return m, nil
+					}
 					// Exit search mode and select current directory for theme selection
 					m.searching = false
 					m.searchInput.Blur()
@@ -72,6 +113,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				case "esc":
 					// Exit search mode
 					m.searching = false
+					m.stopDeepSearch()
 					m.searchInput.Blur()
 					m.searchInput.SetValue("")
 					m.filterFiles()
@@ -85,16 +127,64 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.cursor++
 						m.updateViewport()
 					}
-				case " ", "tab":
+				case "tab":
+					// Switch between this folder and the whole subtree
+					if m.deepSearch {
+						m.stopDeepSearch()
+						m.filterFiles()
+						return m, nil
+					}
+					m.deepSearch = true
+					return m, m.searchSubtree()
+				case "right":
+					if m.deepSearch && m.cursor < len(m.filteredFiles) {
+						return m, m.loadDirectory(m.filteredFiles[m.cursor].Path)
+					}
+					return m, nil
+				case " ":
 					// Space is reserved for directory selection - ignore in search mode
 					return m, nil
 				default:
 					// Handle search input
 					var cmd tea.Cmd
 					m.searchInput, cmd = m.searchInput.Update(msg)
+					if m.deepSearch {
+						return m, tea.Batch(cmd, m.searchSubtree())
+					}
 					m.filterFiles()
 					return m, cmd
 				}
+			} else if m.jumping {
+				switch msg.String() {
+				case "enter":
+					path, err := browse.Resolve(m.jumpInput.Value(), m.directory)
+					if err != nil {
+						m.jumpErr = err
+						return m, nil
+					}
+					m.stopJump()
+					return m, m.loadDirectory(path)
+				case "esc":
+					m.stopJump()
+					m.updateViewport()
+				case "tab":
+					completed, matches := browse.Complete(m.jumpInput.Value(), m.directory)
+					m.jumpInput.SetValue(completed)
+					m.jumpInput.CursorEnd()
+					m.jumpErr = nil
+					m.jumpMatches = nil
+					if len(matches) > 1 {
+						m.jumpMatches = matches
+					}
+				case "ctrl+c":
+					return m, tea.Quit
+				default:
+					var cmd tea.Cmd
+					m.jumpInput, cmd = m.jumpInput.Update(msg)
+					m.jumpErr = nil
+					m.jumpMatches = nil
+					return m, cmd
+				}
 			} else if m.creatingNewDir {
 				switch msg.String() {
 				case "enter":
@@ -104,12 +194,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						err := os.MkdirAll(fullPath, 0755)
 						if err == nil {
 							// Navigate INTO the newly created directory and go to theme selection
-							m.loadDirectory(fullPath)
 							m.creatingNewDir = false
 							m.newDirInput.Blur()
 							m.newDirInput.SetValue("")
-							m.step = stepTheme
-							return m, nil
+							return m, m.selectDirectory(fullPath)
 						}
 					}
 				case "esc":
@@ -128,11 +216,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					// Check if we have a valid selection and it's a directory
 					if m.cursor < len(m.filteredFiles) {
 						entry := m.filteredFiles[m.cursor]
+						if !m.canEnter(entry) {
+							return m, nil
+						}
 						if entry.IsDir {
 							// Navigate into the selected directory AND go to theme selection (like new dir creation)
-							m.loadDirectory(entry.Path)
-							m.step = stepTheme
-							return m, nil
+							return m, m.selectDirectory(entry.Path)
 						}
 					}
 					// If no directory selected or selection is not a directory, proceed to theme selection
@@ -141,15 +230,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				case "right":
 					if m.cursor < len(m.filteredFiles) {
 						entry := m.filteredFiles[m.cursor]
-						if entry.IsDir {
-							m.loadDirectory(entry.Path) // Navigate into directory
+						if entry.IsDir && m.canEnter(entry) {
+							return m, m.loadDirectory(entry.Path) // Navigate into directory
 						}
 					}
 				case "left":
 					// Go up one directory (parent directory)
 					parent := filepath.Dir(m.directory)
 					if parent != m.directory { // Avoid infinite loop at root
-						m.loadDirectory(parent)
+						return m, m.loadDirectory(parent)
 					}
 				case "n":
 					// Create new directory
@@ -161,6 +250,52 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.searching = true
 					m.searchInput.Focus()
 					return m, nil
+				case "S":
+					// Search the whole subtree straight away
+					m.searching = true
+					m.deepSearch = true
+					m.searchInput.Focus()
+					return m, m.searchSubtree()
+				case "1", "2", "3", "4", "5":
+					// Open a bookmarked or recent directory
+					if i := int(msg.String()[0] - '1'); i < len(m.picks) {
+						return m, m.loadDirectory(m.picks[i].Path)
+					}
+					return m, nil
+				case "p":
+					// Pin or unpin the current directory as a bookmark
+					m.places.ToggleBookmark(m.directory)
+					if err := m.places.Save(); err != nil {
+						m.dirErr = fmt.Errorf("can't save bookmarks: %w", err)
+					}
+					m.refreshPicks()
+					m.updateViewport()
+					return m, nil
+				case "f":
+					// Switch between folders only and folders with files
+					m.listOpts.Files = !m.listOpts.Files
+					return m, m.loadDirectory(m.directory)
+				case ".":
+					// Show or hide dotfiles
+					m.listOpts.Hidden = !m.listOpts.Hidden
+					return m, m.loadDirectory(m.directory)
+				case "o":
+					// Cycle the sort order: name, modified, size
+					m.listOpts.Sort = m.listOpts.Sort.Next()
+					if m.listOpts.Sort == browse.SortSize && !m.deepSearch {
+						// Folders are only counted when listing by size
+						return m, m.loadDirectory(m.directory)
+					}
+					m.resort()
+					return m, nil
+				case "/", "g":
+					// Type or paste a path to go straight there
+					m.jumping = true
+					m.jumpInput.SetValue(strings.TrimSuffix(browse.Abbrev(m.directory), "/") + "/")
+					m.jumpInput.CursorEnd()
+					m.jumpInput.Focus()
+					m.updateViewport()
+					return m, textinput.Blink
 				case "up", "k":
 					if m.cursor > 0 {
 						m.cursor--
@@ -185,8 +320,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.String() {
 			case "enter":
 				if _, ok := m.theme.SelectedItem().(themeItem); ok {
-					m.step = stepAuthChoice
-					return m, nil
+					m.step = stepNextOptions
+					m.focusNextField(m.nextFocus)
+					return m, textinput.Blink
 				}
 			case "ctrl+c":
 				return m, tea.Quit
@@ -199,6 +335,44 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.theme, cmd = m.theme.Update(msg)
 			return m, cmd
 
+		case stepNextOptions:
+			switch msg.String() {
+			case "enter":
+				// Auth is validated against the layout on the review screen
+				m.nextErr = scaffold.Config{Next: m.scaffoldConfig().Next}.Validate()
+				if m.nextErr != nil {
+					return m, nil
+				}
+				m.aliasInput.Blur()
+				m.step = stepAuthChoice
+				return m, nil
+			case "tab", "down":
+				m.focusNextField((m.nextFocus + 1) % nextFieldCount)
+				return m, nil
+			case "shift+tab", "up":
+				m.focusNextField((m.nextFocus + nextFieldCount - 1) % nextFieldCount)
+				return m, nil
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc":
+				// Go back to theme step
+				m.aliasInput.Blur()
+				m.nextErr = nil
+				m.step = stepTheme
+				return m, nil
+			}
+			if m.nextFocus == nextFieldAlias {
+				var cmd tea.Cmd
+				m.aliasInput, cmd = m.aliasInput.Update(msg)
+				m.nextErr = nil
+				return m, cmd
+			}
+			switch msg.String() {
+			case " ", "x", "left", "right", "h", "l":
+				m.toggleNextField()
+			}
+			return m, nil
+
 		case stepAuthChoice:
 			switch msg.String() {
 			case "enter":
@@ -207,62 +381,291 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					case "clerk":
 						m.useClerk = true
 						m.useBetterAuth = false
-						m.step = stepProgress
-						m.isRunning = true
-						if themeSelected, ok := m.theme.SelectedItem().(themeItem); ok {
-							return m, tea.Batch(
-								runScript(m.appName.Value(), m.directory, themeSelected.title, m.useClerk, m.useBetterAuth),
-								tickProgress(),
-								tickOutputUpdate(),
-							)
-						}
+						m.clerkScaffold = false
+						m.chooseTooling()
+						return m, nil
+					case "clerk-protected":
+						// Route matchers and organizations are configured before running
+						m.useClerk = true
+						m.useBetterAuth = false
+						m.clerkScaffold = true
+						m.step = stepClerkOptions
+						m.focusClerkField(clerkFieldPublic)
+						return m, textinput.Blink
 					case "better-auth":
 						m.useClerk = false
 						m.useBetterAuth = true
-						m.step = stepProgress
-						m.isRunning = true
-						if themeSelected, ok := m.theme.SelectedItem().(themeItem); ok {
-							return m, tea.Batch(
-								runScript(m.appName.Value(), m.directory, themeSelected.title, m.useClerk, m.useBetterAuth),
-								tickProgress(),
-								tickOutputUpdate(),
-							)
-						}
+						m.clerkScaffold = false
+						m.chooseTooling()
+						return m, nil
 					case "none":
 						m.useClerk = false
 						m.useBetterAuth = false
-						m.step = stepProgress
-						m.isRunning = true
-						if themeSelected, ok := m.theme.SelectedItem().(themeItem); ok {
-							return m, tea.Batch(
-								runScript(m.appName.Value(), m.directory, themeSelected.title, m.useClerk, m.useBetterAuth),
-								tickProgress(),
-								tickOutputUpdate(),
-							)
-						}
+						m.clerkScaffold = false
+						m.chooseTooling()
+						return m, nil
 					}
 					return m, nil
 				}
 			case "ctrl+c":
 				return m, tea.Quit
 			case "esc":
-				// Go back to theme step
-				m.step = stepTheme
-				return m, nil
+				// Go back to project options
+				m.step = stepNextOptions
+				m.focusNextField(m.nextFocus)
+				return m, textinput.Blink
 			}
 			var cmd tea.Cmd
 			m.authChoice, cmd = m.authChoice.Update(msg)
 			return m, cmd
 
+		case stepClerkOptions:
+			switch msg.String() {
+			case "enter":
+				m.chooseTooling()
+				return m, nil
+			case "tab", "down":
+				m.focusClerkField((m.clerkFocus + 1) % clerkFieldCount)
+				return m, nil
+			case "shift+tab", "up":
+				m.focusClerkField((m.clerkFocus + clerkFieldCount - 1) % clerkFieldCount)
+				return m, nil
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc":
+				// Go back to auth step
+				m.clerkPublic.Blur()
+				m.clerkProtected.Blur()
+				m.step = stepAuthChoice
+				return m, nil
+			}
+			var cmd tea.Cmd
+			switch m.clerkFocus {
+			case clerkFieldPublic:
+				m.clerkPublic, cmd = m.clerkPublic.Update(msg)
+			case clerkFieldProtected:
+				m.clerkProtected, cmd = m.clerkProtected.Update(msg)
+			case clerkFieldOrgs:
+				if msg.String() == " " || msg.String() == "x" {
+					m.clerkOrgs = !m.clerkOrgs
+				}
+			}
+			return m, cmd
+
+		case stepTooling:
+			switch msg.String() {
+			case "enter":
+				m.step = stepExtras
+				return m, nil
+			case "esc":
+				// Go back to where the auth choice was made
+				if m.useClerk && m.clerkScaffold {
+					m.step = stepClerkOptions
+					m.focusClerkField(clerkFieldPublic)
+					return m, textinput.Blink
+				}
+				m.step = stepAuthChoice
+				return m, nil
+			case "ctrl+c":
+				return m, tea.Quit
+			}
+			var cmd tea.Cmd
+			m.toolingChoice, cmd = m.toolingChoice.Update(msg)
+			return m, cmd
+
+		case stepExtras:
+			switch msg.String() {
+			case "up", "k":
+				if m.extrasCursor > 0 {
+					m.extrasCursor--
+				}
+			case "down", "j":
+				if m.extrasCursor < len(m.extras)-1 {
+					m.extrasCursor++
+				}
+			case " ", "x":
+				if len(m.extras) > 0 {
+					m.extras[m.extrasCursor].checked = !m.extras[m.extrasCursor].checked
+				}
+			case "enter":
+				m.reviewGeneration()
+			case "esc":
+				m.step = stepTooling
+			case "ctrl+c":
+				return m, tea.Quit
+			}
+			return m, nil
+
+		case stepReview:
+			switch msg.String() {
+			case "enter":
+				// Combinations the generator cannot produce are shown, not run
+				if m.reviewError() != nil {
+					return m, nil
+				}
+				return m, m.startRun()
+			case "v":
+				// Verification only exists in the native pipeline
+				if !m.useScript {
+					m.verify = !m.verify
+					m.showPlan = false
+				}
+				return m, nil
+			case "d":
+				// Toggle the dry-run preview of every command and file
+				if m.showPlan {
+					m.showPlan = false
+					return m, nil
+				}
+				return m, m.previewPlan()
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc":
+				m.showPlan = false
+				m.step = stepExtras
+				return m, nil
+			}
+			if m.showPlan {
+				var cmd tea.Cmd
+				m.outputViewport, cmd = m.outputViewport.Update(msg)
+				return m, cmd
+			}
 
 		case stepProgress:
 			if msg.String() == "ctrl+c" {
 				return m, tea.Quit
 			}
+			cmd, _ := m.handleOutputKey(msg)
+			return m, cmd
 
 		case stepComplete:
-			return m, tea.Quit
+			if m.viewingLog {
+				if cmd, ok := m.handleOutputKey(msg); ok {
+					return m, cmd
+				}
+				switch msg.String() {
+				case "esc", "q":
+					m.viewingLog = false
+					return m, nil
+				case "ctrl+c":
+					return m, tea.Quit
+				}
+				return m, nil
+			}
+
+			if m.err != nil || m.pendingSteps() > 0 {
+				switch msg.String() {
+				case "r":
+					// Retry only the failed step; the footer hides r otherwise
+					if failed := m.failedStep(); failed != "" && m.canResume() {
+						return m, m.startResume(failed)
+					}
+					return m, nil
+				case "c":
+					// Resume from the failed (or next pending) step to the end
+					if m.canResume() {
+						return m, m.startResume("")
+					}
+					return m, nil
+				case "l":
+					m.showLog()
+					return m, nil
+				}
+				return m, tea.Quit
+			}
+
+			actions := m.completeActions()
+			switch msg.String() {
+			case "up", "k":
+				if m.actionCursor > 0 {
+					m.actionCursor--
+				}
+			case "down", "j":
+				if m.actionCursor < len(actions)-1 {
+					m.actionCursor++
+				}
+			case "enter":
+				return m.runCompleteAction(actions[m.actionCursor].id)
+			case "q", "esc", "ctrl+c":
+				return m, tea.Quit
+			}
+			return m, nil
+
+		case stepDevServer:
+			switch msg.String() {
+			case "s":
+				// Toggle: stopping can take a few seconds, so do it off the UI loop
+				srv := m.devServer
+				if srv.Running() {
+					return m, func() tea.Msg { return devServerMsg{err: srv.Stop()} }
+				}
+				return m, func() tea.Msg { return devServerMsg{err: srv.Start()} }
+			case "r":
+				srv := m.devServer
+				return m, func() tea.Msg { return devServerMsg{err: srv.Restart()} }
+			case "esc", "b":
+				// Back to the menu; the server keeps running
+				m.step = stepComplete
+				return m, nil
+			case "q", "ctrl+c":
+				return m, tea.Quit // main stops the server on exit
+			}
+			var cmd tea.Cmd
+			m.outputViewport, cmd = m.outputViewport.Update(msg)
+			return m, cmd
+		}
+
+	case deepSearchMsg:
+		if msg.seq != m.deepSeq || !m.deepSearch {
+			return m, nil // superseded by a newer query
+		}
+		m.deepRunning = false
+		m.deepCancel = nil
+		m.dirErr = msg.err
+		m.filteredFiles = nil
+		for _, match := range msg.matches {
+			m.filteredFiles = append(m.filteredFiles, fileEntry{Name: match.Rel, Path: match.Path, IsDir: true, Matched: match.Indexes})
+		}
+		m.cursor = 0
+		m.viewportStart = 0
+		m.updateViewport()
+		return m, nil
+
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
+	case dirPageMsg:
+		return m, m.handleDirPage(msg)
+
+	case previewMsg:
+		if msg.seq == m.previewSeq {
+			m.preview = msg.preview
+			m.previewCancel = nil
+		}
+		return m, nil
+
+	case spinner.TickMsg:
+		if !m.loading {
+			return m, nil // let the spinner stop
 		}
+		var cmd tea.Cmd
+		m.loadSpinner, cmd = m.loadSpinner.Update(msg)
+		return m, cmd
+
+	case devServerTickMsg:
+		if m.step != stepDevServer {
+			m.serverTicking = false
+			return m, nil
+		}
+		m.refreshDevServer()
+		return m, tickDevServer()
+
+	case devServerMsg:
+		if msg.err != nil {
+			m.notice = fmt.Sprintf("Dev server: %v", msg.err)
+		}
+		m.refreshDevServer()
+		return m, nil
 
 	case progressMsg:
 		if m.isRunning {
@@ -281,19 +684,47 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case outputUpdateMsg:
 		if m.isRunning {
-			// Update viewport with current buffer content, stripping ANSI codes
-			cleanOutput := stripAnsiCodes(liveOutputBuf.String())
-			m.outputViewport.SetContent(cleanOutput)
-			m.outputViewport.GotoBottom() // Auto-scroll to bottom!
+			// Follows new output unless the user scrolled up
+			m.setOutput(liveOutputBuf.String())
+			m.steps = snapshotSteps()
 
 			return m, tickOutputUpdate()
 		}
 
+	case actionDoneMsg:
+		if msg.err != nil {
+			m.notice = fmt.Sprintf("%s failed: %v", msg.action, msg.err)
+		} else {
+			m.notice = msg.action + " finished"
+		}
+		return m, nil
+
+	case planMsg:
+		if m.step == stepReview {
+			plan := stripAnsiCodes(msg.output)
+			if msg.err != nil {
+				plan += fmt.Sprintf("\n❌ The dry run stopped: %v\n", msg.err)
+			}
+			m.outputViewport.SetContent(plan)
+			m.outputViewport.GotoTop()
+			m.showPlan = true
+		}
+		return m, nil
+
 	case completeMsg:
 		m.isRunning = false
 		m.step = stepComplete
 		m.output = msg.output
 		m.err = msg.err
+		m.steps = msg.steps
+		m.checks = msg.checks
+		m.diagnoses = nil
+		switch {
+		case msg.err != nil:
+			m.diagnoses = diagnose.Diagnose(msg.output)
+		case stepWarnings(msg.steps):
+			m.diagnoses = diagnose.Warnings(msg.output)
+		}
 		m.progress.SetPercent(1.0)
 		m.progress2.SetPercent(1.0)
 		m.progress3.SetPercent(1.0)
@@ -316,4 +747,829 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	return m, nil
-}
\ No newline at end of file
+}
+
+// canEnter reports whether a browser entry can be opened, explaining why
+// not in the directory error
+func (m *model) canEnter(entry fileEntry) bool {
+	switch {
+	case entry.Broken:
+		m.dirErr = fmt.Errorf("%s points to %s, which does not exist", entry.Name, entry.Link)
+	case entry.Locked:
+		m.dirErr = fmt.Errorf("%s is locked, it can't be opened", browse.Abbrev(entry.Path))
+	default:
+		return true
+	}
+	return false
+}
+
+// dirPageMsg carries the next page of a directory being listed
+type dirPageMsg struct {
+	seq     int
+	ctx     context.Context
+	path    string
+	opts    browse.Options
+	lister  *browse.Lister
+	first   bool           // the listing was just opened
+	project browse.Project // what lives in path, detected when it opens
+	entries []fileEntry
+	err     error // io.EOF once every entry was read
+}
+
+// dirPageSize is how many entries are read between screen updates
+const dirPageSize = 500
+
+// loadDirectory stops the listing in progress and starts listing path. The
+// current folder stays on screen until the first page arrives. When path
+// can't be opened the closest readable parent is listed and the error kept
+// for the view.
+func (m *model) loadDirectory(path string) tea.Cmd {
+	m.dirErr = nil
+	m.selectOnLoad = false
+	return m.listDirectory(path)
+}
+
+// selectDirectory lists path and picks it as the parent directory once it
+// opens
+func (m *model) selectDirectory(path string) tea.Cmd {
+	cmd := m.loadDirectory(path)
+	m.selectOnLoad = true
+	return cmd
+}
+
+func (m *model) listDirectory(path string) tea.Cmd {
+	if m.loadCancel != nil {
+		m.loadCancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.loadCancel = cancel
+	m.loadSeq++
+	m.loadPath = path
+	m.stopPreview() // read again once the listing opens
+	cmd := readPage(dirPageMsg{seq: m.loadSeq, ctx: ctx, path: path, opts: m.listOpts})
+	if m.loading {
+		return cmd // the spinner is already turning
+	}
+	m.loading = true
+	return tea.Batch(cmd, m.loadSpinner.Tick)
+}
+
+// readPage opens the listing on the first call and reads its next page
+func readPage(page dirPageMsg) tea.Cmd {
+	return func() tea.Msg {
+		page.first = page.lister == nil
+		if page.first {
+			lister, err := browse.Open(page.path, page.opts)
+			if err != nil {
+				page.err = err
+				return page
+			}
+			page.lister = lister
+			page.project = browse.Detect(page.path)
+		}
+		page.entries, page.err = page.lister.Next(page.ctx, dirPageSize)
+		if page.err != nil {
+			page.lister.Close()
+		}
+		return page
+	}
+}
+
+// handleDirPage shows a page of the current listing and asks for the next
+func (m *model) handleDirPage(page dirPageMsg) tea.Cmd {
+	if page.seq != m.loadSeq {
+		if page.err == nil && page.lister != nil {
+			page.lister.Close() // superseded by a newer listing
+		}
+		return nil
+	}
+	if page.first && m.step != stepDirectory && page.path != m.directory {
+		// The user moved on with the folder on screen; don't swap it
+		if page.err == nil {
+			page.lister.Close()
+		}
+		m.stopLoading()
+		return nil
+	}
+	if page.first && page.err != nil && page.err != io.EOF {
+		if m.dirErr == nil {
+			m.dirErr = fmt.Errorf("can't open %s: %s", browse.Abbrev(page.path), browse.Reason(page.err))
+		}
+		m.selectOnLoad = false
+		if parent := filepath.Dir(page.path); parent != page.path {
+			return m.listDirectory(parent)
+		}
+		m.stopLoading()
+		return nil
+	}
+
+	if page.first {
+		m.showDirectory(page.path, page.project)
+		if m.selectOnLoad {
+			m.selectOnLoad = false
+			m.step = stepTheme
+		}
+	}
+	m.addEntries(page.entries)
+	switch {
+	case page.err == io.EOF:
+		m.stopLoading()
+		return nil
+	case page.err != nil:
+		m.stopLoading()
+		if page.ctx.Err() == nil {
+			m.dirErr = fmt.Errorf("listing %s stopped: %s", browse.Abbrev(page.path), browse.Reason(page.err))
+		}
+		return nil
+	}
+	return readPage(page)
+}
+
+// stopLoading cancels the listing in progress
+func (m *model) stopLoading() {
+	if m.loadCancel != nil {
+		m.loadCancel()
+		m.loadCancel = nil
+	}
+	m.loading = false
+	m.selectOnLoad = false
+	m.restorePath = ""
+}
+
+// deepSearchMsg carries the results of a subtree search
+type deepSearchMsg struct {
+	seq     int
+	matches []browse.Match
+	err     error
+}
+
+// deepSearchLimit caps how many subtree matches are listed
+const deepSearchLimit = 200
+
+// searchSubtree cancels the running subtree search and starts one for the
+// current query
+func (m *model) searchSubtree() tea.Cmd {
+	if m.deepCancel != nil {
+		m.deepCancel()
+		m.deepCancel = nil
+	}
+	m.deepSeq++
+	query := strings.TrimSpace(m.searchInput.Value())
+	if query == "" {
+		m.deepRunning = false
+		m.filteredFiles = nil
+		m.cursor = 0
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.deepCancel = cancel
+	m.deepRunning = true
+	seq, root := m.deepSeq, m.directory
+	return func() tea.Msg {
+		matches, err := browse.Search(ctx, root, query, deepSearchLimit)
+		if ctx.Err() != nil {
+			return deepSearchMsg{seq: seq} // dropped, a newer search runs
+		}
+		if err != nil {
+			err = fmt.Errorf("subtree search stopped: %s", browse.Reason(err))
+		}
+		return deepSearchMsg{seq: seq, matches: matches, err: err}
+	}
+}
+
+// stopDeepSearch cancels a running subtree search and leaves subtree mode
+func (m *model) stopDeepSearch() {
+	if m.deepCancel != nil {
+		m.deepCancel()
+		m.deepCancel = nil
+	}
+	m.deepSearch = false
+	m.deepRunning = false
+	m.deepSeq++
+}
+
+// stopJump leaves the path input of the directory browser
+func (m *model) stopJump() {
+	m.jumping = false
+	m.jumpErr = nil
+	m.jumpMatches = nil
+	m.jumpInput.Blur()
+	m.jumpInput.SetValue("")
+}
+
+// Clerk options fields, in focus order
+const (
+	clerkFieldPublic = iota
+	clerkFieldProtected
+	clerkFieldOrgs
+	clerkFieldCount
+)
+
+// focusClerkField moves focus between the Clerk options inputs
+func (m *model) focusClerkField(field int) {
+	m.clerkFocus = field
+	m.clerkPublic.Blur()
+	m.clerkProtected.Blur()
+	switch field {
+	case clerkFieldPublic:
+		m.clerkPublic.Focus()
+	case clerkFieldProtected:
+		m.clerkProtected.Focus()
+	}
+}
+
+// Project options fields, in focus order
+const (
+	nextFieldLanguage = iota
+	nextFieldRouter
+	nextFieldSrcDir
+	nextFieldTurbopack
+	nextFieldCompiler
+	nextFieldAlias
+	nextFieldCount
+)
+
+// focusNextField moves focus between the project options
+func (m *model) focusNextField(field int) {
+	m.nextFocus = field
+	if field == nextFieldAlias {
+		m.aliasInput.Focus()
+	} else {
+		m.aliasInput.Blur()
+	}
+}
+
+// toggleNextField flips the focused project option
+func (m *model) toggleNextField() {
+	switch m.nextFocus {
+	case nextFieldLanguage:
+		m.nextOpts.JavaScript = !m.nextOpts.JavaScript
+	case nextFieldRouter:
+		m.nextOpts.PagesRouter = !m.nextOpts.PagesRouter
+	case nextFieldSrcDir:
+		m.nextOpts.NoSrcDir = !m.nextOpts.NoSrcDir
+	case nextFieldTurbopack:
+		m.nextOpts.NoTurbopack = !m.nextOpts.NoTurbopack
+	case nextFieldCompiler:
+		m.nextOpts.ReactCompiler = !m.nextOpts.ReactCompiler
+	}
+	m.nextErr = nil
+}
+
+// newExecutor returns the executor generation should use
+func (m model) newExecutor() executor.Executor {
+	if m.dryRun {
+		return executor.NewDryRun()
+	}
+	return executor.Real{}
+}
+
+// generationCmd builds the command that generates the project with ex
+func (m model) generationCmd(ex executor.Executor) tea.Cmd {
+	var run tea.Cmd
+	if m.useScript {
+		themeTitle := ""
+		if themeSelected, ok := m.theme.SelectedItem().(themeItem); ok {
+			themeTitle = themeSelected.title
+		}
+		clerk := clerkOptions{
+			Scaffold:        m.clerkScaffold,
+			PublicRoutes:    strings.TrimSpace(m.clerkPublic.Value()),
+			ProtectedRoutes: strings.TrimSpace(m.clerkProtected.Value()),
+			Organizations:   m.clerkOrgs,
+		}
+		run = runScript(m.appName.Value(), m.directory, themeTitle, m.useClerk, m.useBetterAuth, clerk, ex)
+	} else {
+		run = runPipeline(m.scaffoldConfig(), ex, nil, "")
+	}
+	return withDryRunSummary(run, ex)
+}
+
+// withDryRunSummary labels the output of a dry run and adds what it would have done
+func withDryRunSummary(run tea.Cmd, ex executor.Executor) tea.Cmd {
+	dry, isDry := ex.(*executor.DryRun)
+	if !isDry {
+		return run
+	}
+	return func() tea.Msg {
+		msg := run().(completeMsg)
+		msg.output = "=== DRY RUN: nothing is executed or written ===\n" + msg.output +
+			"\n🔍 " + dry.Summary() + "\n"
+		return msg
+	}
+}
+
+// previewPlan dry-runs generation for the review screen
+func (m model) previewPlan() tea.Cmd {
+	run := m.generationCmd(executor.NewDryRun())
+	return func() tea.Msg {
+		msg := run().(completeMsg)
+		return planMsg{output: msg.output, err: msg.err}
+	}
+}
+
+// startRun switches to the progress step and kicks off generation
+func (m *model) startRun() tea.Cmd {
+	if _, ok := m.theme.SelectedItem().(themeItem); !ok {
+		return nil
+	}
+	m.runConfig = m.scaffoldConfig()
+	m.showPlan = false
+	if !m.dryRun {
+		// Remember the parent directory for the quick picks of the next run
+		m.places.Visit(m.runConfig.ParentDir, time.Now())
+		m.places.Save()
+		m.refreshPicks()
+	}
+	return m.beginProgress(m.generationCmd(m.newExecutor()))
+}
+
+// startResume continues the last run from its recorded state, stopping
+// after until when it is set (to retry a single step)
+func (m *model) startResume(until string) tea.Cmd {
+	ex := m.newExecutor()
+	return m.beginProgress(withDryRunSummary(resumePipeline(m.runConfig, ex, until), ex))
+}
+
+// beginProgress resets the progress screen and starts run alongside the tickers
+func (m *model) beginProgress(run tea.Cmd) tea.Cmd {
+	m.step = stepProgress
+	m.isRunning = true
+	m.err = nil
+	m.steps = nil
+	m.checks = nil
+	m.clearOutputSearch()
+	m.outputNotice = ""
+	m.outputContent = ""
+	m.outputViewport.SetContent("")
+	m.outputViewport.GotoBottom()
+	liveStepsMu.Lock()
+	liveSteps = nil
+	liveStepsMu.Unlock()
+	return tea.Batch(
+		run,
+		m.progress.SetPercent(0),
+		m.progress2.SetPercent(0),
+		m.progress3.SetPercent(0),
+		tickProgress(),
+		tickOutputUpdate(),
+	)
+}
+
+// failedStep returns the name of the step the last run failed on
+func (m model) failedStep() string {
+	for _, s := range m.steps {
+		if s.Status == scaffold.StatusFailed {
+			return s.Name
+		}
+	}
+	return ""
+}
+
+// pendingSteps counts steps the last run did not get to
+func (m model) pendingSteps() int {
+	pending := 0
+	for _, s := range m.steps {
+		if s.Status == scaffold.StatusPending {
+			pending++
+		}
+	}
+	return pending
+}
+
+// stepWarnings reports whether any step finished with warnings
+func stepWarnings(steps []scaffold.StepResult) bool {
+	for _, s := range steps {
+		if len(s.Warnings) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// canResume reports whether the last run can be retried or resumed
+func (m model) canResume() bool {
+	return !m.useScript && (m.failedStep() != "" || m.pendingSteps() > 0)
+}
+
+// chooseTooling blurs the auth inputs and shows the linting choice
+func (m *model) chooseTooling() {
+	m.clerkPublic.Blur()
+	m.clerkProtected.Blur()
+	m.step = stepTooling
+}
+
+// reviewError reports why the reviewed config cannot be generated. The
+// bash script ignores the project options, so only the step engine checks.
+func (m model) reviewError() error {
+	if m.useScript {
+		return nil
+	}
+	return m.scaffoldConfig().Validate()
+}
+
+// reviewGeneration shows the review screen
+func (m *model) reviewGeneration() {
+	m.showPlan = false
+	m.step = stepReview
+}
+
+// completeAction is an entry of the menu shown after a successful run
+type completeAction struct {
+	id    string
+	title string
+}
+
+// actionDoneMsg reports that an interactive action handed the terminal back
+type actionDoneMsg struct {
+	action string
+	err    error
+}
+
+// completeActions lists what can be done with the new project
+func (m model) completeActions() []completeAction {
+	var actions []completeAction
+	if !m.dryRun {
+		editor := "VS Code"
+		if args := editorCommand(); len(args) > 0 && args[0] != "code" {
+			editor = filepath.Base(args[0])
+		}
+		dev := "Start the dev server (npm run dev)"
+		if m.devServer != nil && m.devServer.Running() {
+			dev = "Return to the running dev server"
+		}
+		actions = append(actions,
+			completeAction{id: "editor", title: "Open the project in " + editor},
+			completeAction{id: "dev", title: dev},
+			completeAction{id: "copy", title: "Copy the cd command to the clipboard"},
+		)
+	}
+	actions = append(actions,
+		completeAction{id: "log", title: "View the full log"},
+		completeAction{id: "another", title: "Start another project with the same settings"},
+		completeAction{id: "quit", title: "Exit"},
+	)
+	return actions
+}
+
+// editorCommand returns $VISUAL or $EDITOR split into args, falling back to VS Code
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if args := strings.Fields(os.Getenv(env)); len(args) > 0 {
+			return args
+		}
+	}
+	if _, err := exec.LookPath("code"); err == nil {
+		return []string{"code"}
+	}
+	return nil
+}
+
+// runCompleteAction performs the selected completion menu entry
+func (m model) runCompleteAction(id string) (tea.Model, tea.Cmd) {
+	projectDir := m.runConfig.ProjectDir()
+	m.notice = ""
+
+	switch id {
+	case "editor":
+		args := editorCommand()
+		if len(args) == 0 {
+			m.notice = "Set $EDITOR or install VS Code's `code` command to open the project"
+			return m, nil
+		}
+		cmd := exec.Command(args[0], append(args[1:], projectDir)...)
+		cmd.Dir = projectDir
+		return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+			return actionDoneMsg{action: "Editor", err: err}
+		})
+
+	case "dev":
+		if m.devServer == nil {
+			m.devServer = devserver.New(projectDir)
+		}
+		if err := m.devServer.Start(); err != nil {
+			m.notice = fmt.Sprintf("Could not start the dev server: %v", err)
+			return m, nil
+		}
+		m.step = stepDevServer
+		m.outputViewport.GotoBottom()
+		m.refreshDevServer()
+		if m.serverTicking {
+			return m, nil
+		}
+		m.serverTicking = true
+		return m, tickDevServer()
+
+	case "copy":
+		cdCmd := "cd " + projectDir
+		if err := clipboard.WriteAll(cdCmd); err != nil {
+			m.notice = fmt.Sprintf("Could not reach the clipboard (%v), run: %s", err, cdCmd)
+		} else {
+			m.notice = "Copied: " + cdCmd
+		}
+		return m, nil
+
+	case "log":
+		m.showLog()
+		return m, nil
+
+	case "another":
+		return m, tea.Batch(textinput.Blink, m.startAnother())
+	}
+
+	return m, tea.Quit
+}
+
+// showLog opens the full run output on the completion screen
+func (m *model) showLog() {
+	m.outputContent = logview.Clean(m.output)
+	m.outputNotice = ""
+	m.refreshOutput()
+	m.outputViewport.GotoBottom()
+	m.viewingLog = true
+}
+
+// refreshDevServer copies the dev server output into the viewport, following
+// new lines unless the user scrolled up
+func (m *model) refreshDevServer() {
+	follow := m.outputViewport.AtBottom()
+	m.outputViewport.SetContent(logview.Clean(m.devServer.Snapshot().Output))
+	if follow {
+		m.outputViewport.GotoBottom()
+	}
+}
+
+// startAnother goes back to the first step keeping directory, theme and auth choices
+func (m *model) startAnother() tea.Cmd {
+	if m.devServer != nil {
+		m.devServer.Stop()
+		m.devServer = nil
+	}
+	m.err = nil
+	m.output = ""
+	m.steps = nil
+	m.checks = nil
+	m.diagnoses = nil
+	m.notice = ""
+	m.actionCursor = 0
+	m.viewingLog = false
+	m.appName.SetValue("")
+	m.appName.Focus()
+	m.step = stepAppName
+	return m.loadDirectory(m.directory) // pick up the project we just created
+}
+
+// click is the last mouse click, to spot double-clicks
+type click struct {
+	target string
+	at     time.Time
+}
+
+// doubleClickTime is how close two clicks on the same target must be
+const doubleClickTime = 400 * time.Millisecond
+
+// clicked records a click on target and reports whether it completes a
+// double-click
+func (m *model) clicked(target string) bool {
+	double := target == m.lastClick.target && time.Since(m.lastClick.at) < doubleClickTime
+	m.lastClick = click{target: target, at: time.Now()}
+	if double {
+		m.lastClick = click{} // a third click starts over
+	}
+	return double
+}
+
+// handleMouse scrolls with the wheel and selects what was clicked; a
+// double-click acts like the key that opens or confirms it
+func (m model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	wheel := 0
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		wheel = -1
+	case tea.MouseButtonWheelDown:
+		wheel = 1
+	}
+	press := msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft
+
+	switch m.step {
+	case stepDirectory:
+		if m.creatingNewDir || m.jumping {
+			return m, nil
+		}
+		if wheel != 0 {
+			m.scrollList(wheel * 3)
+			return m, nil
+		}
+		if !press || msg.X >= m.listWidth() {
+			return m, nil
+		}
+		row := msg.Y - strings.Count(m.directoryHeader(), "\n")
+		i := m.viewportStart + row
+		if row < 0 || i >= m.viewportEnd || i >= len(m.filteredFiles) {
+			return m, nil
+		}
+		m.cursor = i
+		m.updateViewport()
+		if m.clicked("dir:" + m.filteredFiles[i].Path) {
+			return m.Update(tea.KeyMsg{Type: tea.KeyRight})
+		}
+		return m, nil
+
+	case stepTheme, stepAuthChoice, stepTooling:
+		l := &m.theme
+		switch m.step {
+		case stepAuthChoice:
+			l = &m.authChoice
+		case stepTooling:
+			l = &m.toolingChoice
+		}
+		switch {
+		case wheel < 0:
+			l.CursorUp()
+		case wheel > 0:
+			l.CursorDown()
+		case press:
+			i, ok := listItemAt(*l, m.View(), msg.Y)
+			if !ok {
+				return m, nil
+			}
+			l.Select(i)
+			if m.clicked(fmt.Sprintf("%d:%d", m.step, i)) {
+				return m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			}
+		}
+		return m, nil
+
+	case stepProgress, stepComplete, stepDevServer:
+		// The output viewport scrolls with the wheel
+		var cmd tea.Cmd
+		m.outputViewport, cmd = m.outputViewport.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// scrollList moves the listing by delta rows, dragging the cursor along when
+// it would leave the screen
+func (m *model) scrollList(delta int) {
+	height := m.listHeight()
+	last := max(len(m.filteredFiles)-height, 0)
+	m.viewportStart = min(max(m.viewportStart+delta, 0), last)
+	m.cursor = min(max(m.cursor, m.viewportStart), m.viewportStart+height-1)
+	m.updateViewport()
+}
+
+// listItemAt finds the item of a list drawn at screen row y of view. Rows
+// are found by looking for the titles of the items on the current page.
+func listItemAt(l list.Model, view string, y int) (int, bool) {
+	listView := l.View()
+	at := strings.Index(view, listView)
+	if at < 0 {
+		return 0, false
+	}
+	row := y - strings.Count(view[:at], "\n")
+	lines := strings.Split(ansi.Strip(listView), "\n")
+
+	items := l.VisibleItems()
+	start, end := l.Paginator.GetSliceBounds(len(items))
+	line, found := 0, -1
+	for i := start; i < end; i++ {
+		item, ok := items[i].(interface{ Title() string })
+		if !ok {
+			return 0, false
+		}
+		for line < len(lines) && !strings.Contains(lines[line], item.Title()) {
+			line++
+		}
+		if line == len(lines) {
+			break
+		}
+		if row < line {
+			break
+		}
+		found = i
+		line++
+	}
+	// The last item only covers its title and description lines
+	if found < 0 || found == end-1 && row > line {
+		return 0, false
+	}
+	return found, true
+}
+
+// setOutput shows the run output in the viewport, following new lines only
+// while the view is at the bottom; scrolling up pauses it until G
+func (m *model) setOutput(output string) {
+	follow := m.outputViewport.AtBottom()
+	m.outputContent = logview.Clean(output)
+	m.refreshOutput()
+	if follow {
+		m.outputViewport.GotoBottom()
+	}
+}
+
+// refreshOutput renders the output with the search matches marked
+func (m *model) refreshOutput() {
+	query := m.outputSearch.Value()
+	m.outputMatches = logview.Find(m.outputContent, query)
+	if m.outputMatch >= len(m.outputMatches) {
+		m.outputMatch = max(len(m.outputMatches)-1, 0)
+	}
+	active := -1
+	if len(m.outputMatches) > 0 {
+		active = m.outputMatches[m.outputMatch]
+	}
+	m.outputViewport.SetContent(logview.Highlight(m.outputContent, query, m.outputMatches, active, matchStyle, currentMatchStyle))
+}
+
+// showMatch scrolls the current search match into view
+func (m *model) showMatch() {
+	m.refreshOutput()
+	if len(m.outputMatches) > 0 {
+		m.outputViewport.SetYOffset(m.outputMatches[m.outputMatch] - m.outputViewport.Height/3)
+	}
+}
+
+// clearOutputSearch drops the query and its marks
+func (m *model) clearOutputSearch() {
+	m.searchingOutput = false
+	m.outputSearch.Blur()
+	m.outputSearch.SetValue("")
+	m.outputMatches = nil
+	m.outputMatch = 0
+}
+
+// handleOutputKey scrolls, searches and copies the output viewport. It
+// reports false for the keys it leaves to the screen: ctrl+c, and esc or q
+// when no search is active.
+func (m *model) handleOutputKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.searchingOutput {
+		switch msg.String() {
+		case "ctrl+c":
+			return nil, false
+		case "enter":
+			m.searchingOutput = false
+			m.outputSearch.Blur()
+			return nil, true
+		case "esc":
+			m.clearOutputSearch()
+			m.refreshOutput()
+			return nil, true
+		}
+		var cmd tea.Cmd
+		m.outputSearch, cmd = m.outputSearch.Update(msg)
+		// Jump to the first match from the top as the query changes
+		m.outputMatch = 0
+		m.showMatch()
+		return cmd, true
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return nil, false
+	case "esc":
+		if m.outputSearch.Value() == "" {
+			return nil, false
+		}
+		m.clearOutputSearch()
+		m.refreshOutput()
+	case "/":
+		m.clearOutputSearch()
+		m.searchingOutput = true
+		m.outputSearch.Focus()
+		return textinput.Blink, true
+	case "n", "N":
+		if n := len(m.outputMatches); n > 0 {
+			if msg.String() == "n" {
+				m.outputMatch = (m.outputMatch + 1) % n
+			} else {
+				m.outputMatch = (m.outputMatch + n - 1) % n
+			}
+			m.showMatch()
+		}
+	case "G":
+		m.outputViewport.GotoBottom()
+	case "g":
+		m.outputViewport.GotoTop()
+	case "y", "Y":
+		text, what := m.outputContent, "the whole log"
+		if msg.String() == "y" {
+			lines := strings.Split(m.outputContent, "\n")
+			from := min(m.outputViewport.YOffset, len(lines))
+			to := min(from+m.outputViewport.Height, len(lines))
+			text = strings.Join(lines[from:to], "\n")
+			what = fmt.Sprintf("%d visible lines", to-from)
+		}
+		if err := clipboard.WriteAll(logview.Plain(text)); err != nil {
+			m.outputNotice = fmt.Sprintf("Could not reach the clipboard: %v", err)
+		} else {
+			m.outputNotice = "Copied " + what
+		}
+	default:
+		var cmd tea.Cmd
+		m.outputViewport, cmd = m.outputViewport.Update(msg)
+		return cmd, true
+	}
+	return nil, true
+}
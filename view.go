@@ -2,10 +2,16 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/WillyV3/nextjs-templater/internal/browse"
+	"github.com/WillyV3/nextjs-templater/internal/devserver"
+	"github.com/WillyV3/nextjs-templater/internal/scaffold"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -44,6 +50,431 @@ func (m model) getBorderedTitleStyleCompact() lipgloss.Style {
 		MarginBottom(1)
 }
 
+// renderSteps shows the engine's step list as a wrapped line of status badges
+func (m model) renderSteps() string {
+	if len(m.steps) == 0 {
+		return ""
+	}
+
+	var badges []string
+	for _, s := range m.steps {
+		switch s.Status {
+		case scaffold.StatusRunning:
+			badges = append(badges, stepRunningStyle.Render("◐ "+s.Name))
+		case scaffold.StatusDone:
+			badges = append(badges, stepDoneStyle.Render("✓ "+s.Name))
+		case scaffold.StatusFailed:
+			badges = append(badges, stepFailedStyle.Render("✗ "+s.Name))
+		case scaffold.StatusSkipped:
+			badges = append(badges, stepPendingStyle.Render("↷ "+s.Name))
+		default:
+			badges = append(badges, stepPendingStyle.Render("○ "+s.Name))
+		}
+	}
+
+	return lipgloss.NewStyle().Width(m.width - 4).Render(strings.Join(badges, "  "))
+}
+
+// maxCheckErrors caps how many parsed errors show per verification check
+const maxCheckErrors = 3
+
+// renderChecks shows the verification results as a pass/fail table
+func (m model) renderChecks() string {
+	width := 0
+	for _, c := range m.checks {
+		if len(c.Title) > width {
+			width = len(c.Title)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("Verification\n")
+	for _, c := range m.checks {
+		row := fmt.Sprintf("%-*s  %5.1fs", width, c.Title, c.Duration.Seconds())
+		if c.Passed {
+			b.WriteString(stepDoneStyle.Render("✓ "+row) + "\n")
+			continue
+		}
+		b.WriteString(stepFailedStyle.Render("✗ "+row) + "\n")
+		for i, e := range c.Errors {
+			if i == maxCheckErrors {
+				b.WriteString(excerptStyle.Render(fmt.Sprintf("    … %d more in the log", len(c.Errors)-i)) + "\n")
+				break
+			}
+			b.WriteString(excerptStyle.Render("    "+e) + "\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// maxDiagnoses caps how many diagnoses fit on the error screen
+const maxDiagnoses = 2
+
+// renderDiagnoses shows matched failure signatures with log excerpts and fixes
+func (m model) renderDiagnoses() string {
+	if len(m.diagnoses) == 0 {
+		return ""
+	}
+
+	width := m.width - 10
+	var sections []string
+	for i, d := range m.diagnoses {
+		if i == maxDiagnoses {
+			sections = append(sections, excerptStyle.Render(fmt.Sprintf("…and %d more", len(m.diagnoses)-maxDiagnoses)))
+			break
+		}
+		var b strings.Builder
+		b.WriteString(stepFailedStyle.Render("Diagnosis: "+d.Title) + "\n")
+		for _, line := range d.Excerpt {
+			b.WriteString(excerptStyle.Render("  │ "+truncate(line, width)) + "\n")
+		}
+		for _, fix := range d.Fixes {
+			b.WriteString("  • " + fix + "\n")
+		}
+		sections = append(sections, strings.TrimRight(b.String(), "\n"))
+	}
+
+	return diagnosisStyle.Width(m.width - 6).Render(strings.Join(sections, "\n\n"))
+}
+
+// renderPicks lists the bookmarked and recent directories with their keys
+func (m model) renderPicks() string {
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	var b strings.Builder
+	b.WriteString(hintStyle.Render("Quick picks") + "\n")
+	for i, pick := range m.picks {
+		mark := " "
+		if pick.Bookmarked {
+			mark = "★"
+		}
+		line := fmt.Sprintf("%d %s %s", i+1, mark, truncate(browse.Abbrev(pick.Path), m.width-8))
+		if pick.Path == m.directory {
+			line = folderStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// highlightMatches renders name in style with the characters at the matched
+// byte offsets picked out
+func highlightMatches(name string, matched []int, style lipgloss.Style) string {
+	if len(matched) == 0 {
+		return style.Render(name)
+	}
+	hit := make(map[int]bool, len(matched))
+	for _, i := range matched {
+		hit[i] = true
+	}
+
+	var b strings.Builder
+	run, runHit := "", false
+	flush := func() {
+		if run == "" {
+			return
+		}
+		if runHit {
+			b.WriteString(matchStyle.Render(run))
+		} else {
+			b.WriteString(style.Render(run))
+		}
+		run = ""
+	}
+	for i, r := range name {
+		if hit[i] != runHit {
+			flush()
+			runHit = hit[i]
+		}
+		run += string(r)
+	}
+	flush()
+	return b.String()
+}
+
+// renderEntry styles a browser row: directories end in a slash, symlinks show
+// their target and entries that can't be opened are marked. project is what
+// lives in the entry, only known for the highlighted one.
+func renderEntry(entry fileEntry, mode browse.SortMode, project browse.Project) string {
+	style := fileStyle
+	switch {
+	case entry.Broken, entry.Locked:
+		style = lockedStyle
+	case entry.IsDir:
+		style = folderStyle
+	}
+	line := highlightMatches(entry.Name, entry.Matched, style)
+	if entry.IsDir {
+		line += style.Render("/")
+	}
+	if entry.Locked {
+		line = lockedStyle.Render("🔒 ") + line
+	}
+	if entry.Link != "" {
+		target := " → " + entry.Link
+		if entry.Broken {
+			target += " (broken)"
+		}
+		line += lockedStyle.Render(target)
+	}
+	if tags := project.Tags(); len(tags) > 0 {
+		line += tagStyle.Render(" [" + strings.Join(tags, "] [") + "]")
+	}
+	if detail := sortDetail(entry, mode); detail != "" {
+		line += lockedStyle.Render(" · " + detail)
+	}
+	return line
+}
+
+// sortDetail is what the listing is sorted by for one entry, like "2h ago"
+// or "12 items"; nothing when sorted by name
+func sortDetail(entry fileEntry, mode browse.SortMode) string {
+	switch {
+	case entry.Name == ".." || entry.Broken || entry.Locked:
+		return ""
+	case mode == browse.SortModified && !entry.ModTime.IsZero():
+		return humanAge(time.Since(entry.ModTime))
+	case mode == browse.SortSize && entry.IsDir:
+		if entry.Size >= browse.MaxCounted {
+			return fmt.Sprintf("%d+ items", browse.MaxCounted)
+		}
+		if entry.Size == 1 {
+			return "1 item"
+		}
+		return fmt.Sprintf("%d items", entry.Size)
+	case mode == browse.SortSize:
+		return humanBytes(entry.Size)
+	}
+	return ""
+}
+
+// humanAge shortens a duration to its largest unit, like "3d ago"
+func humanAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+	return fmt.Sprintf("%dy ago", int(d.Hours()/24/365))
+}
+
+// humanBytes formats a file size like "4.2 kB"
+func humanBytes(n int64) string {
+	if n < 1000 {
+		return fmt.Sprintf("%d B", n)
+	}
+	size, unit := float64(n), 0
+	for size >= 1000 && unit < 4 {
+		size /= 1000
+		unit++
+	}
+	return fmt.Sprintf("%.1f %cB", size, "kMGT"[unit-1])
+}
+
+// projectWarning says what already lives in a directory the app would be
+// created in, empty when there is nothing there
+func projectWarning(p browse.Project) string {
+	var what string
+	switch {
+	case p.Next != "":
+		what = "a Next.js " + strings.TrimLeft(p.Next, "^~") + " project"
+	case p.JS:
+		what = "a JavaScript project"
+	case p.Git:
+		what = "a git repository"
+	default:
+		return ""
+	}
+	if p.JS && p.Git {
+		what += " in a git repository"
+	}
+	return "⚠ already " + what
+}
+
+// renderPreview shows the highlighted directory's children in a pane of the
+// given size
+func (m model) renderPreview(width, height int) string {
+	p := m.preview
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	inner := width - 2 // border and padding
+	selected := fileEntry{}
+	if m.cursor >= 0 && m.cursor < len(m.filteredFiles) {
+		selected = m.filteredFiles[m.cursor]
+	}
+	var lines []string
+	if selected.Path != "" {
+		lines = append(lines, folderStyle.Bold(true).Render(truncate(filepath.Base(selected.Path)+"/", inner)))
+	}
+	if warning := projectWarning(p.Project); warning != "" {
+		lines = append(lines, warningStyle.Render(truncate(warning, inner)))
+	}
+
+	switch {
+	case selected.Path == "":
+	case selected.Locked || selected.Broken:
+		lines = append(lines, hintStyle.Render("can't be opened"))
+	case !selected.IsDir:
+		lines = append(lines, hintStyle.Render("not a folder"))
+	case p.Path != selected.Path:
+		lines = append(lines, hintStyle.Render("loading…"))
+	case p.Err != nil:
+		lines = append(lines, stepFailedStyle.Render(truncate("⚠ "+browse.Reason(p.Err), inner)))
+	case len(p.Entries) == 0:
+		lines = append(lines, hintStyle.Render("empty"))
+	default:
+		room := height - len(lines)
+		shown := p.Entries
+		if len(shown) > room {
+			// Keep the last line to say how many are left out
+			shown = shown[:max(room-1, 0)]
+		}
+		for _, e := range shown {
+			style, name := fileStyle, e.Name
+			if e.IsDir {
+				style, name = folderStyle, name+"/"
+			}
+			lines = append(lines, style.Render(truncate(name, inner)))
+		}
+		more := len(p.Entries) - len(shown)
+		switch {
+		case p.More:
+			lines = append(lines, hintStyle.Render(fmt.Sprintf("… %d+ more", more+1)))
+		case more > 0:
+			lines = append(lines, hintStyle.Render(fmt.Sprintf("… %d more", more)))
+		}
+	}
+
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return previewStyle.Width(width - 1).Render(strings.Join(lines, "\n"))
+}
+
+// directoryHeader renders the browser above the listing, down to the
+// separator; mouse clicks count its lines to find the row under the pointer
+func (m model) directoryHeader() string {
+	var b strings.Builder
+	// Use ASCII art if terminal is large enough
+	// The ASCII art is about 74 chars wide and 3 lines tall
+	if m.width >= 80 && m.height >= 25 {
+		// Use compact style (no padding) for ASCII art
+		b.WriteString(m.getBorderedTitleStyleCompact().Render(getChooseDirAscii()))
+	} else {
+		// Use regular style with plain text for small terminals
+		b.WriteString(m.getBorderedTitleStyle().Render("Choose Directory"))
+	}
+	current := m.directory
+	if m.places.Bookmarked(m.directory) {
+		current += " ★"
+	}
+	if m.loading {
+		loaded := len(m.files)
+		if loaded > 0 && m.files[0].Name == ".." {
+			loaded--
+		}
+		status := fmt.Sprintf("listing… %d so far", loaded)
+		if m.loadPath != m.directory {
+			status = "opening " + browse.Abbrev(m.loadPath) + "…"
+		}
+		current += "  " + m.loadSpinner.View() + lockedStyle.Render(status)
+	}
+	b.WriteString(fmt.Sprintf("\nCurrent: %s\n", current))
+	if warning := projectWarning(m.dirProject); warning != "" {
+		b.WriteString(warningStyle.Render(warning+" — the app would be created inside it") + "\n")
+	}
+	if m.dirErr != nil {
+		b.WriteString(stepFailedStyle.Render("⚠ "+m.dirErr.Error()) + "\n")
+	}
+
+	// Show appropriate input based on mode
+	if m.creatingNewDir {
+		b.WriteString(fmt.Sprintf("Creating new directory in: %s\n", m.directory))
+		b.WriteString("Name: " + m.newDirInput.View() + "\n")
+	} else if m.searching && m.deepSearch {
+		b.WriteString(fmt.Sprintf("Search below %s: %s\n", browse.Abbrev(m.directory), m.searchInput.View()))
+	} else if m.searching {
+		b.WriteString("Search: " + m.searchInput.View() + "\n")
+	} else if m.jumping {
+		b.WriteString("Go to: " + m.jumpInput.View() + "\n")
+		hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+		switch {
+		case m.jumpErr != nil:
+			b.WriteString(stepFailedStyle.Render("⚠ "+m.jumpErr.Error()) + "\n")
+		case len(m.jumpMatches) > 0:
+			b.WriteString(hintStyle.Render(truncate(strings.Join(m.jumpMatches, "  "), m.width-2)) + "\n")
+		default:
+			b.WriteString(hintStyle.Render("tab: complete • enter: go • esc: cancel") + "\n")
+		}
+	}
+
+	if m.showPicks() {
+		b.WriteString(m.renderPicks())
+	}
+
+	b.WriteString(browserSeparator(m.width) + "\n")
+	return b.String()
+}
+
+// listWidth is the width of the listing column, narrower when the preview
+// pane sits next to it
+func (m model) listWidth() int {
+	if m.width < previewMinWidth {
+		return m.width
+	}
+	return (m.width - 2) * 3 / 5
+}
+
+// browserSeparator is the rule around the listing, fitting the terminal
+func browserSeparator(width int) string {
+	separator := strings.Repeat("━", width-2)
+	if len(separator) < 20 {
+		separator = "━━━━━━━━━━━━━━━━━━━━"
+	}
+	return separator
+}
+
+// outputFooter is the line under the output viewport: the search being
+// typed, or the search position, copy feedback and keys. following adds
+// whether new output is being followed.
+func (m model) outputFooter(following bool, keys string) string {
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	if m.searchingOutput {
+		return m.outputSearch.View() + hintStyle.Render(fmt.Sprintf("  %d matching lines • enter: keep • esc: clear", len(m.outputMatches)))
+	}
+	var parts []string
+	if following && !m.outputViewport.AtBottom() {
+		parts = append(parts, "⏸ paused")
+	}
+	if query := m.outputSearch.Value(); query != "" {
+		if len(m.outputMatches) == 0 {
+			parts = append(parts, fmt.Sprintf("no match for %q", query))
+		} else {
+			parts = append(parts, fmt.Sprintf("match %d/%d for %q • n/N: next/prev", m.outputMatch+1, len(m.outputMatches), query))
+		}
+	}
+	if m.outputNotice != "" {
+		parts = append(parts, m.outputNotice)
+	}
+	parts = append(parts, keys)
+	return hintStyle.Render(truncate(strings.Join(parts, " • "), m.width-2))
+}
+
+// truncate shortens plain text to width runes, ending in an ellipsis
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if width < 1 || len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
+
 func (m model) View() string {
 	switch m.step {
 	case stepAppName:
@@ -83,51 +514,43 @@ func (m model) View() string {
 
 	case stepDirectory:
 		var b strings.Builder
-		// Use ASCII art if terminal is large enough
-		// The ASCII art is about 74 chars wide and 3 lines tall
-		if m.width >= 80 && m.height >= 25 {
-			// Use compact style (no padding) for ASCII art
-			b.WriteString(m.getBorderedTitleStyleCompact().Render(getChooseDirAscii()))
-		} else {
-			// Use regular style with plain text for small terminals
-			b.WriteString(m.getBorderedTitleStyle().Render("Choose Directory"))
-		}
-		b.WriteString(fmt.Sprintf("\nCurrent: %s\n", m.directory))
-
-		// Show appropriate input based on mode
-		if m.creatingNewDir {
-			b.WriteString(fmt.Sprintf("Creating new directory in: %s\n", m.directory))
-			b.WriteString("Name: " + m.newDirInput.View() + "\n")
-		} else if m.searching {
-			b.WriteString("Search: " + m.searchInput.View() + "\n")
-		}
-
-		// Create separator that fits terminal width
-		separator := strings.Repeat("━", m.width-2)
-		if len(separator) < 20 {
-			separator = "━━━━━━━━━━━━━━━━━━━━"
-		}
-		b.WriteString(separator + "\n")
+		b.WriteString(m.directoryHeader())
+		separator := browserSeparator(m.width)
 
 		// Show files and directories (only viewport)
+		var rows []string
 		for i := m.viewportStart; i < m.viewportEnd; i++ {
 			if i >= len(m.filteredFiles) {
 				break
 			}
 			entry := m.filteredFiles[i]
-			line := entry.Name
-			if entry.IsDir {
-				line = folderStyle.Render(line + "/")
-			} else {
-				line = fileStyle.Render(line)
+			var project browse.Project
+			if i == m.cursor && m.preview.Path == entry.Path {
+				project = m.preview.Project
 			}
+			line := renderEntry(entry, m.listOpts.Sort, project)
 
 			if i == m.cursor && !m.creatingNewDir && !m.searching {
 				line = selectedStyle.Render(line)
 			} else if i == m.cursor && m.searching {
 				line = selectedStyle.Render(line)
 			}
-			b.WriteString(line + "\n")
+			rows = append(rows, line)
+		}
+		if m.width >= previewMinWidth && len(rows) > 0 {
+			// Listing on the left, the highlighted folder on the right
+			listWidth := m.listWidth()
+			for i, row := range rows {
+				row = lipgloss.NewStyle().MaxWidth(listWidth - 1).Render(row)
+				rows[i] = row + strings.Repeat(" ", listWidth-lipgloss.Width(row))
+			}
+			previewHeight := max(len(rows), m.listHeight())
+			b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+				strings.Join(rows, "\n"), m.renderPreview(m.width-2-listWidth, previewHeight)) + "\n")
+		} else {
+			for _, row := range rows {
+				b.WriteString(row + "\n")
+			}
 		}
 
 		b.WriteString(separator + "\n")
@@ -137,11 +560,24 @@ func (m model) View() string {
 			b.WriteString("Type folder name • enter: create & select • esc: cancel")
 		} else if m.searching {
 			totalFiles := len(m.filteredFiles)
-			if totalFiles == 0 {
-				b.WriteString("No matches • esc: exit search")
-			} else {
-				info := fmt.Sprintf("(%d/%d matches) ↑↓: navigate • enter: select • esc: exit search",
-					m.cursor+1, totalFiles)
+			scope := "tab: search subtree"
+			if m.deepSearch {
+				scope = "tab: this folder only"
+			}
+			switch {
+			case m.deepRunning:
+				b.WriteString("Searching subfolders… • " + scope + " • esc: exit search")
+			case m.deepSearch && strings.TrimSpace(m.searchInput.Value()) == "":
+				b.WriteString(fmt.Sprintf("Type to search folders up to %d levels down • %s • esc: exit search", browse.SearchDepth, scope))
+			case totalFiles == 0:
+				b.WriteString("No matches • " + scope + " • esc: exit search")
+			default:
+				info := fmt.Sprintf("(%d/%d matches) ↑↓: navigate • enter: select • %s • esc: exit search",
+					m.cursor+1, totalFiles, scope)
+				if m.deepSearch {
+					info = fmt.Sprintf("(%d/%d matches) ↑↓: navigate • →: open • enter: select • %s • esc: exit search",
+						m.cursor+1, totalFiles, scope)
+				}
 
 				// Wrap to terminal width, keeping key:action pairs together
 				if len(info) > m.width-4 {
@@ -181,8 +617,20 @@ func (m model) View() string {
 				}
 			}
 		} else {
-			info := fmt.Sprintf("(%d/%d) ↑↓/jk: navigate • →: open • ←: up dir • enter: select • s: search • n: new folder • esc: back",
-				m.cursor+1, len(m.filteredFiles))
+			pin := "p: pin folder"
+			if m.places.Bookmarked(m.directory) {
+				pin = "p: unpin folder"
+			}
+			picks := ""
+			switch len(m.picks) {
+			case 0:
+			case 1:
+				picks = "1: quick pick • "
+			default:
+				picks = fmt.Sprintf("1-%d: quick pick • ", len(m.picks))
+			}
+			info := fmt.Sprintf("(%d/%d, %s) ↑↓/jk: navigate • →: open • ←: up dir • enter: select • s/S: search folder/subtree • /: go to path • %s%s • n: new folder • f: files • .: hidden • o: sort • esc: back",
+				m.cursor+1, len(m.filteredFiles), m.listMode(), picks, pin)
 
 			// Wrap to terminal width, keeping key:action pairs together
 			if len(info) > m.width-4 {
@@ -243,6 +691,46 @@ func (m model) View() string {
 			"Enter: continue • Esc: back • Ctrl+C: quit",
 		)
 
+	case stepNextOptions:
+		labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+		focusedLabelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("86")).Bold(true)
+		choice := func(first bool, a, b string) string {
+			if first {
+				return selectedStyle.Render(a) + labelStyle.Render(" / "+b)
+			}
+			return labelStyle.Render(a+" / ") + selectedStyle.Render(b)
+		}
+		o := m.nextOpts
+		rows := []struct{ label, value string }{
+			{"Language", choice(!o.JavaScript, "TypeScript", "JavaScript")},
+			{"Router", choice(!o.PagesRouter, "App Router", "Pages Router")},
+			{"src/ directory", choice(!o.NoSrcDir, "Yes", "No")},
+			{"Dev bundler", choice(!o.NoTurbopack, "Turbopack", "Webpack")},
+			{"React Compiler", choice(!o.ReactCompiler, "Off", "On")},
+			{"Import alias", m.aliasInput.View()},
+		}
+		var body strings.Builder
+		for i, row := range rows {
+			label := labelStyle.Render(fmt.Sprintf("  %-16s", row.label))
+			if i == m.nextFocus {
+				label = focusedLabelStyle.Render(fmt.Sprintf("▸ %-16s", row.label))
+			}
+			body.WriteString(label + row.value + "\n")
+		}
+		if m.nextErr != nil {
+			body.WriteString("\n" + stepFailedStyle.Render("⚠ "+m.nextErr.Error()) + "\n")
+		}
+		if m.useScript {
+			body.WriteString("\n" + labelStyle.Render("Project options are not available with --script") + "\n")
+		}
+
+		return fmt.Sprintf(
+			"\n%s\n\n%s\n%s",
+			m.getBorderedTitleStyle().Render("Project Options"),
+			body.String(),
+			"Tab/↑↓: next option • Space/←→: toggle • Enter: continue • Esc: back • Ctrl+C: quit",
+		)
+
 	case stepAuthChoice:
 		// Use ASCII art if terminal is large enough
 		var titleSection string
@@ -261,24 +749,216 @@ func (m model) View() string {
 			"Enter: continue • Esc: back • Ctrl+C: quit",
 		)
 
+	case stepClerkOptions:
+		var titleSection string
+		if m.width >= 80 && m.height >= 25 {
+			titleSection = m.getBorderedTitleStyleCompact().Render(getChooseAuthAscii())
+		} else {
+			titleSection = m.getBorderedTitleStyle().Render("Clerk Options")
+		}
+
+		labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+		focusedLabelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("86")).Bold(true)
+		label := func(field int, text string) string {
+			if m.clerkFocus == field {
+				return focusedLabelStyle.Render("▸ " + text)
+			}
+			return labelStyle.Render("  " + text)
+		}
+
+		orgs := "[ ] Organizations (multi-tenant, org switcher + /org-selection)"
+		if m.clerkOrgs {
+			orgs = "[x] Organizations (multi-tenant, org switcher + /org-selection)"
+		}
+
+		return fmt.Sprintf(
+			"\n%s\n%s\n%s\n\n%s\n%s\n\n%s\n\n%s",
+			titleSection,
+			label(clerkFieldPublic, "Public routes (comma separated)"),
+			"  "+m.clerkPublic.View(),
+			label(clerkFieldProtected, "Protected routes (comma separated, empty = everything not public)"),
+			"  "+m.clerkProtected.View(),
+			label(clerkFieldOrgs, orgs),
+			"Tab/↑↓: next field • Space: toggle • Enter: continue • Esc: back • Ctrl+C: quit",
+		)
+
+	case stepTooling:
+		return fmt.Sprintf(
+			"\n%s\n\n%s\n\n%s",
+			m.getBorderedTitleStyle().Render("Linting & Formatting"),
+			m.toolingChoice.View(),
+			"Enter: continue • Esc: back • Ctrl+C: quit",
+		)
+
+	case stepExtras:
+		descStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+		var rows strings.Builder
+		for i, e := range m.extras {
+			box := "[ ]"
+			if e.checked {
+				box = "[x]"
+			}
+			line := box + " " + e.title
+			if i == m.extrasCursor {
+				rows.WriteString(selectedStyle.Render("▸ "+line) + "\n")
+			} else {
+				rows.WriteString("  " + line + "\n")
+			}
+			rows.WriteString(descStyle.Render("      "+e.desc) + "\n")
+		}
+		if m.useScript {
+			rows.WriteString("\n" + descStyle.Render("Extras are not available with --script"))
+		}
+
+		return fmt.Sprintf(
+			"\n%s\n\n%s\n\n%s",
+			m.getBorderedTitleStyle().Render("Extras"),
+			strings.TrimRight(rows.String(), "\n"),
+			"↑↓: move • Space: toggle • Enter: continue • Esc: back • Ctrl+C: quit",
+		)
+
+	case stepReview:
+		cfg := m.scaffoldConfig()
+		labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Width(14)
+
+		theme := cfg.Theme
+		if theme == "" {
+			theme = "default"
+		}
+		auth := string(cfg.Auth)
+		if cfg.Auth == scaffold.AuthClerk && cfg.Clerk.Scaffold {
+			auth = "clerk + protected routes"
+			if cfg.Clerk.Organizations {
+				auth += " + organizations"
+			}
+		}
+		mode := "native step engine"
+		if m.useScript {
+			mode = "bash script (--script)"
+		}
+		if m.dryRun {
+			mode += ", dry run"
+		}
+		gitLabel := fmt.Sprintf("commit %q", cfg.Git.CommitMessage())
+		switch {
+		case m.useScript:
+			gitLabel = "left to create-next-app (--script)"
+		case cfg.Git.Skip:
+			gitLabel = "off"
+		case cfg.Git.Push:
+			gitLabel += ", push to " + cfg.Git.Remote
+		case cfg.Git.Remote != "":
+			gitLabel += ", origin " + cfg.Git.Remote
+		}
+		toolingLabel := "eslint"
+		if tooling, ok := m.toolingChoice.SelectedItem().(toolingItem); ok && !m.useScript {
+			toolingLabel = tooling.title
+		}
+		var picked []string
+		for _, e := range m.extras {
+			if e.checked {
+				picked = append(picked, e.title)
+			}
+		}
+		extrasLabel := "none"
+		if m.useScript {
+			extrasLabel = "not available with --script"
+		} else if len(picked) > 0 {
+			extrasLabel = strings.Join(picked, ", ")
+		}
+		versionsLabel := cfg.Versions.Name
+		if cfg.Versions.Description != "" {
+			versionsLabel += " (" + cfg.Versions.Description + ")"
+		}
+		if m.useScript {
+			versionsLabel = "latest, not pinned (--script)"
+		}
+		layoutLabel := cfg.Layout()
+		if m.useScript {
+			layoutLabel = "create-next-app defaults (--script)"
+		}
+		verifyLabel := "off"
+		switch {
+		case m.useScript:
+			verifyLabel = "not available with --script"
+		case cfg.Verify && !cfg.TypeScript():
+			verifyLabel = "lint, next build"
+		case cfg.Verify:
+			verifyLabel = "tsc --noEmit, lint, next build"
+		}
+
+		rows := [][2]string{
+			{"App name", cfg.AppName},
+			{"Project", cfg.ProjectDir()},
+			{"Theme", theme},
+			{"Layout", layoutLabel},
+			{"Auth", auth},
+			{"Versions", versionsLabel},
+			{"Mode", mode},
+			{"Verify", verifyLabel},
+			{"Tooling", toolingLabel},
+			{"Git", gitLabel},
+			{"Extras", extrasLabel},
+		}
+		var summary strings.Builder
+		for _, row := range rows {
+			summary.WriteString(labelStyle.Render(row[0]) + row[1] + "\n")
+		}
+
+		body := summary.String()
+		if err := m.reviewError(); err != nil {
+			body += "\n" + stepFailedStyle.Render("⚠ "+err.Error()+" (Esc to change it)") + "\n"
+		}
+		help := "Enter: create project • d: dry-run preview • v: toggle verify • Esc: back • Ctrl+C: quit"
+		if m.showPlan {
+			// Shrink the shared viewport to fit below the summary
+			vp := m.outputViewport
+			vp.Height = m.height - 18
+			if vp.Height < 3 {
+				vp.Height = 3
+			}
+			body += "\n" + vp.View()
+			help = "↑↓: scroll preview • d: hide preview • Enter: create project • Esc: back"
+		}
+
+		return fmt.Sprintf(
+			"\n%s\n%s\n%s",
+			m.getBorderedTitleStyle().Render("Review"),
+			body,
+			lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(help),
+		)
 
 	case stepProgress:
 		return fmt.Sprintf(
-			"\n%s\n\n%s\n\n%s\n\n%s\n%s\n%s\n\n%s\n\n%s",
+			"\n%s\n\n%s\n\n%s\n\n%s\n%s\n%s\n\n%s\n%s\n\n%s",
 			m.getBorderedTitleStyle().Render("Creating Your Project"),
 			fmt.Sprintf("Name of your Next.js App: %s", m.appName.Value()),
 			fmt.Sprintf("Parent Directory: %s", m.directory),
 			m.progress.View(),
 			m.progress2.View(),
 			m.progress3.View(),
+			m.renderSteps(),
 			m.outputViewport.View(),
-			lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("Installation output • Ctrl+C to cancel"),
+			m.outputFooter(true, "↑↓/wheel: scroll • G: follow • /: search • y/Y: copy visible/all • Ctrl+C: cancel"),
 		)
 
 	case stepComplete:
+		if m.viewingLog {
+			return fmt.Sprintf(
+				"\n%s\n\n%s\n\n%s",
+				titleStyle.Render("Full Log"),
+				m.outputViewport.View(),
+				m.outputFooter(false, "↑/↓: scroll • g/G: top/bottom • /: search • y/Y: copy visible/all • esc: back"),
+			)
+		}
+
 		status := "Project created successfully!"
 		if m.err != nil {
 			status = "Error: " + m.err.Error()
+		} else if pending := m.pendingSteps(); pending > 0 {
+			status = fmt.Sprintf("Step succeeded, %d steps remaining", pending)
+		} else if m.dryRun {
+			status = "Dry run complete: nothing was created. The plan is printed when you exit."
 		}
 
 		// Create styled components like the beginning page
@@ -307,22 +987,210 @@ func (m model) View() string {
 
 		// Get ASCII art and create thank you message
 		thankYouMessage := fmt.Sprintf("%s\n\n%s", getAsciiArt(), status)
+		controls := "↑/↓: navigate • enter: select • q: exit"
+		message := "Your Next.js project has been created with shadcn/ui components!"
+		if m.err != nil || m.pendingSteps() > 0 {
+			controls = "l: view log • any other key: exit"
+		}
+		if m.canResume() {
+			thankYouMessage += "\n\n" + m.renderSteps()
+			controls = "c: resume • l: view log • any other key: exit"
+			if m.failedStep() != "" {
+				controls = "r: retry failed step • c: resume • l: view log • any other key: exit"
+			}
+			message = fmt.Sprintf("Completed steps are recorded in %s.\nResume later with: nextui resume %s",
+				scaffold.StateFile, m.runConfig.ProjectDir())
+		}
+
+		if len(m.checks) > 0 {
+			message = m.renderChecks() + "\n\n" + message
+		}
+
+		if m.err != nil && len(m.diagnoses) > 0 {
+			// The diagnosis is what matters now; drop the ASCII art to make room
+			return fmt.Sprintf(
+				"\n%s\n%s\n%s\n%s",
+				headerStyle.Render(status+"\n\n"+m.renderSteps()),
+				m.renderDiagnoses(),
+				messageStyle.Render(message),
+				controlsStyle.Render(controls),
+			)
+		}
+
+		if m.err == nil && m.pendingSteps() == 0 {
+			message += "\n\n" + m.renderActions()
+			if m.height < 40 {
+				// The menu matters more than the art on short terminals
+				thankYouMessage = status
+			}
+			if len(m.diagnoses) > 0 {
+				// Something was skipped quietly; explain it instead of the art
+				return fmt.Sprintf(
+					"\n%s\n%s\n%s\n%s",
+					headerStyle.Render(status),
+					m.renderDiagnoses(),
+					messageStyle.Render(message),
+					controlsStyle.Render(controls),
+				)
+			}
+		}
 
 		return fmt.Sprintf(
 			"\n%s\n%s\n%s",
 			headerStyle.Render(thankYouMessage),
-			messageStyle.Render("Your Next.js project has been created with shadcn/ui components!"),
-			controlsStyle.Render("Press any key to exit"),
+			messageStyle.Render(message),
+			controlsStyle.Render(controls),
+		)
+
+	case stepDevServer:
+		return fmt.Sprintf(
+			"\n%s\n\n%s\n\n%s\n\n%s",
+			m.getBorderedTitleStyle().Render("Dev Server"),
+			m.renderDevServerStatus(),
+			m.outputViewport.View(),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("s: start/stop • r: restart • ↑/↓: scroll • esc: back • q: quit"),
 		)
 	}
 
 	return ""
 }
 
+// renderDevServerStatus summarizes the managed dev server in one line
+func (m model) renderDevServerStatus() string {
+	snap := m.devServer.Snapshot()
+	switch snap.Status {
+	case devserver.StatusStarting:
+		return stepRunningStyle.Render("◌ Starting npm run dev in " + m.devServer.Dir)
+	case devserver.StatusReady:
+		status := "● Ready on " + snap.URL() + ", waiting for a 200"
+		if snap.Code != 0 {
+			status += fmt.Sprintf(" (last answer %d)", snap.Code)
+		}
+		return stepRunningStyle.Render(status)
+	case devserver.StatusHealthy:
+		return stepDoneStyle.Render("● Running at " + snap.URL() + " (200 OK)")
+	case devserver.StatusExited:
+		status := "✗ Dev server exited"
+		if snap.Err != nil {
+			status += ": " + snap.Err.Error()
+		}
+		return stepFailedStyle.Render(status)
+	}
+	return stepPendingStyle.Render("■ Stopped")
+}
+
+// renderActions shows the completion menu with the last action's feedback
+func (m model) renderActions() string {
+	var b strings.Builder
+	b.WriteString("What next?\n")
+	for i, a := range m.completeActions() {
+		if i == m.actionCursor {
+			b.WriteString(selectedStyle.Render("▸ "+a.title) + "\n")
+		} else {
+			b.WriteString("  " + a.title + "\n")
+		}
+	}
+	if m.notice != "" {
+		b.WriteString("\n" + excerptStyle.Render(m.notice))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
 func main() {
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	useScript := flag.Bool("script", false, "run the legacy bash script instead of the native step pipeline")
+	dryRun := flag.Bool("dry-run", false, "print the commands and files generation would run and write, without doing it")
+	verifyRun := flag.Bool("verify", false, "typecheck, lint and build the project once it is generated")
+	noGit := flag.Bool("no-git", false, "skip the git repository and initial commit")
+	gitAuthor := flag.String("git-author", "", "author of the initial commit, as \"Name <email>\" (defaults to your git config)")
+	gitMessage := flag.String("git-message", scaffold.DefaultCommitMessage, "message of the initial commit")
+	gitRemote := flag.String("git-remote", "", "URL added as the origin remote")
+	gitPush := flag.Bool("git-push", false, "push the initial commit to --git-remote")
+	profile := flag.String("profile", "", "version profile pinning every npm package: "+strings.Join(scaffold.ProfileNames(), ", ")+" (default from "+scaffold.UserProfilesPath()+" or the built-in one)")
+	flag.Parse()
+
+	m := initialModel()
+	m.useScript = *useScript
+	m.dryRun = *dryRun
+	m.verify = *verifyRun
+	m.git = scaffold.GitOptions{
+		Skip:    *noGit,
+		Author:  *gitAuthor,
+		Message: *gitMessage,
+		Remote:  *gitRemote,
+		Push:    *gitPush,
+	}
+	if m.git.Push && m.git.Remote == "" {
+		fmt.Println("Error: --git-push needs --git-remote")
+		os.Exit(1)
+	}
+	if _, err := m.git.AuthorEnv(); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	versions, err := scaffold.LoadProfile(*profile)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	m.versions = versions
+
+	// nextui resume <path> continues a failed run from its recorded state
+	switch flag.Arg(0) {
+	case "":
+	case "resume":
+		// flag stops at the first argument, so anything after resume would
+		// be taken for the path or silently dropped
+		for _, arg := range flag.Args()[1:] {
+			if strings.HasPrefix(arg, "-") {
+				fmt.Printf("Error: flags go before the command: nextui %s resume [path]\n", arg)
+				os.Exit(2)
+			}
+		}
+		if flag.NArg() > 2 {
+			fmt.Println("Error: usage: nextui [flags] resume [path]")
+			os.Exit(2)
+		}
+		path := flag.Arg(1)
+		if path == "" {
+			path = "."
+		}
+		state, err := scaffold.LoadState(path)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		m.useScript = false
+		m.runConfig = state.Config
+		m.verify = state.Config.Verify
+		m.git = state.Config.Git
+		m.versions = state.Config.Versions
+		m.appName.SetValue(state.Config.AppName)
+		m.directory = state.Config.ParentDir
+		// The resumed run replaces the first directory listing
+		m.stopLoading()
+		m.initCmd = m.startResume("")
+	default:
+		fmt.Printf("Error: unknown command %q, usage: nextui [flags] [resume [path]]\n", flag.Arg(0))
+		os.Exit(2)
+	}
+
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	final, err := p.Run()
+	if err != nil {
 		fmt.Printf("Error: %v", err)
 		os.Exit(1)
 	}
+
+	fm, ok := final.(model)
+	if !ok {
+		return
+	}
+	// Don't leave a dev server behind
+	if fm.devServer != nil {
+		fm.devServer.Stop()
+	}
+	// Dry runs print their plan once the alt screen is gone
+	if fm.dryRun && fm.output != "" {
+		fmt.Print(stripAnsiCodes(fm.output))
+	}
 }
\ No newline at end of file